@@ -3,10 +3,13 @@
 package main
 
 import (
+	"chess/internal/client/command"
 	"chess/internal/client/display"
+	"chess/internal/client/session"
 )
 
-func handleExit() (restart bool) {
+func handleExit(s *session.Session) (restart bool) {
+	command.AutoSave(s)
 	display.Println(display.Cyan, "Goodbye!")
 	return false
 }
\ No newline at end of file
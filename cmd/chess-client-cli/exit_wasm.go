@@ -4,9 +4,11 @@ package main
 
 import (
 	"chess/internal/client/display"
+	"chess/internal/client/session"
 )
 
-func handleExit() (restart bool) {
+// handleExit skips auto-save, the browser has no filesystem to write to
+func handleExit(s *session.Session) (restart bool) {
 	display.Println(display.Cyan, "Goodbye!")
 
 	display.Println(display.Yellow, "\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -3,6 +3,7 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -11,9 +12,28 @@ import (
 	"chess/internal/client/command"
 	"chess/internal/client/display"
 	"chess/internal/client/session"
+	"chess/internal/config"
+)
+
+var (
+	configPath   = flag.String("config", "", "JSON file of flag values, e.g. {\"timeout\": \"30s\"}; explicit flags take precedence")
+	autoSavePath = flag.String("autosave", "chess-autosave.pgn", "PGN file written on exit when a game is active (empty to disable)")
+	pollInterval = flag.Duration("poll-interval", session.DefaultPollInterval, "Delay between computer move polls when long-polling is unavailable")
+	pollAttempts = flag.Int("poll-attempts", session.DefaultPollAttempts, "Minimum polls before giving up on a computer move")
+	timeout      = flag.Duration("timeout", api.HttpTimeout, "Default API request timeout (long-poll and analysis use a longer one)")
 )
 
 func main() {
+	flag.Parse()
+	display.InitColors()
+
+	if *configPath != "" {
+		if err := config.Apply(flag.CommandLine, *configPath); err != nil {
+			display.Println(display.Red, "Error: %v", err)
+			os.Exit(1)
+		}
+	}
+
 	for {
 		if !runClient() {
 			break
@@ -30,9 +50,12 @@ func runClient() (restart bool) {
 	}()
 
 	s := &session.Session{
-		APIBaseURL: defaultAPIBase,
-		Client:     api.New(defaultAPIBase),
-		Verbose:    false,
+		APIBaseURL:   defaultAPIBase,
+		Client:       api.New(defaultAPIBase, *timeout),
+		Verbose:      false,
+		AutoSavePath: *autoSavePath,
+		PollInterval: *pollInterval,
+		PollAttempts: *pollAttempts,
 	}
 
 	// Initialize simple input scanner
@@ -65,7 +88,7 @@ func runClient() (restart bool) {
 
 		// Check for exit commands
 		if line == "exit" || line == "quit" || line == "x" {
-			return handleExit()
+			return handleExit(s)
 		}
 
 		// Check for verbose flag
@@ -131,6 +154,9 @@ func buildPrompt(s *session.Session) string {
 		}
 	}
 
+	if s.IsReviewing() {
+		b.Add(display.Yellow, fmt.Sprintf(" (review %d)", s.ViewPly))
+	}
+
 	return display.Prompt(b.String())
 }
-
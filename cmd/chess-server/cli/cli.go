@@ -1,14 +1,22 @@
 package cli
 
 import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"text/tabwriter"
 	"time"
 
+	"chess/internal/server/board"
+	"chess/internal/server/core"
+	"chess/internal/server/pgn"
+	"chess/internal/server/service"
 	"chess/internal/server/storage"
 
 	"github.com/google/uuid"
@@ -19,7 +27,7 @@ import (
 // Run is the entry point for the CLI mini-app
 func Run(args []string) error {
 	if len(args) == 0 {
-		return fmt.Errorf("subcommand required: init, delete, query, user")
+		return fmt.Errorf("subcommand required: init, delete, query, export-pgn, verify, show, user")
 	}
 
 	switch args[0] {
@@ -29,9 +37,15 @@ func Run(args []string) error {
 		return runDelete(args[1:])
 	case "query":
 		return runQuery(args[1:])
+	case "export-pgn":
+		return runExportPGN(args[1:])
+	case "verify":
+		return runVerify(args[1:])
+	case "show":
+		return runShow(args[1:])
 	case "user":
 		if len(args) < 2 {
-			return fmt.Errorf("user subcommand required: add, delete, set-password, set-hash, set-email, set-username, list")
+			return fmt.Errorf("user subcommand required: add, import, delete, set-password, set-hash, set-email, set-username, list")
 		}
 		return runUser(args[1], args[2:])
 	default:
@@ -95,6 +109,12 @@ func runQuery(args []string) error {
 	path := fs.String("path", "", "Database file path (required)")
 	gameID := fs.String("gameId", "", "Game ID to filter (optional, * for all)")
 	playerID := fs.String("playerId", "", "Player ID to filter (optional, * for all)")
+	limit := fs.Int("limit", 0, "Maximum games to show (0 for all)")
+	offset := fs.Int("offset", 0, "Number of matching games to skip")
+	since := fs.String("since", "", "Only games started on or after this date (YYYY-MM-DD or RFC3339)")
+	until := fs.String("until", "", "Only games started before the end of this date (YYYY-MM-DD or RFC3339)")
+	asJSON := fs.Bool("json", false, "Print the page of games as a JSON array")
+	activity := fs.Bool("activity", false, "Include each game's move count and last move time")
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -103,6 +123,18 @@ func runQuery(args []string) error {
 	if *path == "" {
 		return fmt.Errorf("database path required")
 	}
+	if *limit < 0 || *offset < 0 {
+		return fmt.Errorf("-limit and -offset must not be negative")
+	}
+
+	q := storage.GameQuery{GameID: *gameID, PlayerID: *playerID, Limit: *limit, Offset: *offset, Activity: *activity}
+	var err error
+	if q.Since, err = parseQueryTime(*since, false); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if q.Until, err = parseQueryTime(*until, true); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
 
 	store, err := storage.NewStore(*path, false)
 	if err != nil {
@@ -110,41 +142,403 @@ func runQuery(args []string) error {
 	}
 	defer store.Close()
 
-	games, err := store.QueryGames(*gameID, *playerID)
+	games, total, err := store.QueryGamesPaged(q)
 	if err != nil {
 		return fmt.Errorf("query failed: %w", err)
 	}
 
-	if len(games) == 0 {
+	if *asJSON {
+		return printJSON(games)
+	}
+
+	if total == 0 {
 		fmt.Println("No games found")
 		return nil
 	}
+	if len(games) == 0 {
+		fmt.Printf("No games at offset %d, %d game(s) match\n", *offset, total)
+		return nil
+	}
 
 	// Print results in tabular format
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "Game ID\tWhite Player\tBlack Player\tStart Time")
-	fmt.Fprintln(w, strings.Repeat("-", 80))
+	if *activity {
+		fmt.Fprintln(w, "Game ID\tWhite Player\tBlack Player\tStart Time\tResult\tMoves\tLast Move")
+		fmt.Fprintln(w, strings.Repeat("-", 135))
+	} else {
+		fmt.Fprintln(w, "Game ID\tWhite Player\tBlack Player\tStart Time\tResult")
+		fmt.Fprintln(w, strings.Repeat("-", 105))
+	}
 
 	for _, g := range games {
 		whiteInfo := fmt.Sprintf("%s (T%d)", g.WhitePlayerID[:8], g.WhiteType)
 		blackInfo := fmt.Sprintf("%s (T%d)", g.BlackPlayerID[:8], g.BlackType)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s",
 			g.GameID[:8]+"...",
 			whiteInfo,
 			blackInfo,
 			g.StartTimeUTC.Format("2006-01-02 15:04:05"),
+			resultText(g),
 		)
+		if *activity {
+			lastMove := "never"
+			if g.LastMoveUTC != nil {
+				lastMove = g.LastMoveUTC.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "\t%d\t%s", *g.MoveCount, lastMove)
+		}
+		fmt.Fprintln(w)
 	}
 	w.Flush()
 
-	fmt.Printf("\nFound %d game(s)\n", len(games))
+	fmt.Printf("\nShowing %d-%d of %d game(s)\n", *offset+1, *offset+len(games), total)
+	return nil
+}
+
+// printJSON writes records as an indented JSON array, empty results print []
+func printJSON[T any](records []T) error {
+	if records == nil {
+		records = []T{}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// parseQueryTime parses a date or RFC3339 timestamp, a bare date used as an
+// upper bound covers that whole day
+func parseQueryTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func runExportPGN(args []string) error {
+	fs := flag.NewFlagSet("export-pgn", flag.ContinueOnError)
+	path := fs.String("path", "", "Database file path (required)")
+	out := fs.String("out", "", "Output PGN file path (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return fmt.Errorf("database path required")
+	}
+	if *out == "" {
+		return fmt.Errorf("output path required")
+	}
+
+	store, err := storage.NewStore(*path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	defer store.Close()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+
+	// Games are read and written one at a time so only a single game and its moves are held in memory
+	exported, skipped := 0, 0
+	err = store.EachGame(func(g storage.GameRecord) error {
+		moves, err := store.GetMoves(g.GameID)
+		if err != nil {
+			return fmt.Errorf("failed to read moves for game %s: %w", g.GameID, err)
+		}
+
+		ucis := make([]string, len(moves))
+		for i, m := range moves {
+			ucis[i] = m.MoveUCI
+		}
+
+		// PGN results do not tell how a game ended, the standard Termination
+		// tag does and the Reason tag keeps the exact stored reason
+		extra := []pgn.Tag{{Name: "GameId", Value: g.GameID}}
+		if termination := pgn.Termination(g.Reason); termination != "" {
+			extra = append(extra,
+				pgn.Tag{Name: "Termination", Value: termination},
+				pgn.Tag{Name: "Reason", Value: g.Reason})
+		}
+
+		game := &pgn.Game{
+			Event:      "Chess Game",
+			Date:       g.StartTimeUTC,
+			Round:      "-",
+			White:      playerName(g.WhitePlayerID, g.WhiteType, g.WhiteLevel),
+			Black:      playerName(g.BlackPlayerID, g.BlackType, g.BlackLevel),
+			Result:     g.Result,
+			InitialFEN: g.InitialFEN,
+			Moves:      ucis,
+			SAN:        storedSAN(moves),
+			Extra:      extra,
+		}
+
+		if err := pgn.Write(w, game); err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping game %s: %v\n", g.GameID, err)
+			skipped++
+			return nil
+		}
+		exported++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	fmt.Printf("Exported %d game(s) to %s\n", exported, *out)
+	if skipped > 0 {
+		fmt.Printf("Skipped %d game(s) with invalid move data\n", skipped)
+	}
+	return nil
+}
+
+// playerName returns a PGN player name for a stored player slot
+func playerName(playerID string, playerType, level int) string {
+	if core.PlayerType(playerType) == core.PlayerComputer {
+		return fmt.Sprintf("Stockfish (level %d)", level)
+	}
+	return playerID
+}
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	path := fs.String("path", "", "Database file path (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return fmt.Errorf("database path required")
+	}
+
+	store, err := storage.NewStore(*path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	defer store.Close()
+
+	issues := 0
+
+	problems, err := store.IntegrityCheck()
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Printf("integrity: %s\n", p)
+	}
+	issues += len(problems)
+
+	orphans, err := store.GetOrphanedMoveGameIDs()
+	if err != nil {
+		return fmt.Errorf("orphan check failed: %w", err)
+	}
+	for _, id := range orphans {
+		fmt.Printf("orphan: moves reference missing game %s\n", id)
+	}
+	issues += len(orphans)
+
+	games, err := store.QueryGames("*", "*")
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	for _, g := range games {
+		moves, err := store.GetMoves(g.GameID)
+		if err != nil {
+			return fmt.Errorf("failed to read moves for game %s: %w", g.GameID, err)
+		}
+		for _, problem := range verifyGame(g, moves) {
+			fmt.Printf("game %s: %s\n", g.GameID, problem)
+			issues++
+		}
+	}
+
+	fmt.Printf("Checked %d game(s)\n", len(games))
+	if issues > 0 {
+		return fmt.Errorf("verification failed: %d issue(s) found", issues)
+	}
+	fmt.Println("No issues found")
 	return nil
 }
 
+// verifyGame replays stored moves and reports where the FEN chain breaks
+func verifyGame(g storage.GameRecord, moves []storage.MoveRecord) []string {
+	b, err := board.ParseFEN(g.InitialFEN)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid initial FEN: %v", err)}
+	}
+
+	var problems []string
+	for i, m := range moves {
+		if m.MoveNumber != i+1 {
+			problems = append(problems, fmt.Sprintf("move %d: expected move number %d", m.MoveNumber, i+1))
+			return problems
+		}
+		if m.PlayerColor != b.Turn().String() {
+			problems = append(problems, fmt.Sprintf("move %d: recorded for %s but %s to move", m.MoveNumber, m.PlayerColor, b.Turn()))
+		}
+
+		next, err := b.ApplyMove(m.MoveUCI)
+		if err != nil {
+			// Later moves cannot be replayed once the chain is broken
+			problems = append(problems, fmt.Sprintf("move %d: %v", m.MoveNumber, err))
+			return problems
+		}
+		if fen := next.ToFEN(); fen != m.FENAfterMove {
+			problems = append(problems, fmt.Sprintf("move %d: stored FEN %q does not match replayed %q", m.MoveNumber, m.FENAfterMove, fen))
+			return problems
+		}
+		b = next
+	}
+	return problems
+}
+
+func runShow(args []string) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	path := fs.String("path", "", "Database file path (required)")
+	gameID := fs.String("gameId", "", "Game ID to show (required)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return fmt.Errorf("database path required")
+	}
+	if *gameID == "" || *gameID == "*" {
+		return fmt.Errorf("game ID required")
+	}
+
+	store, err := storage.NewStore(*path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	defer store.Close()
+
+	games, err := store.QueryGames(*gameID, "")
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("game not found: %s", *gameID)
+	}
+	g := games[0]
+
+	moves, err := store.GetMoves(g.GameID)
+	if err != nil {
+		return fmt.Errorf("failed to read moves: %w", err)
+	}
+
+	// Final position comes from the last stored FEN
+	fen := g.InitialFEN
+	ucis := make([]string, len(moves))
+	for i, m := range moves {
+		ucis[i] = m.MoveUCI
+		fen = m.FENAfterMove
+	}
+
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return fmt.Errorf("invalid stored FEN: %w", err)
+	}
+
+	fmt.Printf("Game: %s\n", g.GameID)
+	fmt.Printf("White: %s\n", playerName(g.WhitePlayerID, g.WhiteType, g.WhiteLevel))
+	fmt.Printf("Black: %s\n", playerName(g.BlackPlayerID, g.BlackType, g.BlackLevel))
+	fmt.Printf("Started: %s\n\n", g.StartTimeUTC.Format("2006-01-02 15:04:05"))
+	fmt.Println(b.ToASCII(board.ASCIIOptions{}))
+	fmt.Printf("\nFEN: %s\n", fen)
+
+	// Prefer stored SAN, then replayed SAN, then the stored UCI if the moves cannot be replayed
+	list := storedSAN(moves)
+	if list == nil {
+		list, err = pgn.MovesToSAN(g.InitialFEN, ucis)
+		if err != nil {
+			fmt.Printf("Warning: replay failed (%v), showing UCI moves\n", err)
+			list = ucis
+		}
+	}
+
+	if len(list) > 0 {
+		fmt.Println("\nMoves:")
+		printMoveList(pgn.Pair(g.InitialFEN, list))
+	}
+
+	undone, err := store.GetUndoLog(g.GameID)
+	if err != nil {
+		return fmt.Errorf("failed to read undo log: %w", err)
+	}
+	if len(undone) > 0 {
+		fmt.Println("\nTaken back:")
+		for _, u := range undone {
+			fmt.Printf("  move %d %s at %s\n", u.MoveNumber, u.MoveUCI, u.UndoneAt.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	fmt.Printf("\nResult: %s\n", resultText(g))
+	return nil
+}
+
+// storedSAN returns the moves' stored SAN, nil when any move was recorded
+// without it so the caller replays the game instead
+func storedSAN(moves []storage.MoveRecord) []string {
+	sans := make([]string, len(moves))
+	for i, m := range moves {
+		if m.MoveSAN == "" {
+			return nil
+		}
+		sans[i] = m.MoveSAN
+	}
+	return sans
+}
+
+// resultText returns a game's result with the manner of ending, e.g. "1-0 (resignation)"
+func resultText(g storage.GameRecord) string {
+	if g.Reason == "" {
+		return g.Result
+	}
+	return fmt.Sprintf("%s (%s)", g.Result, g.Reason)
+}
+
+// printMoveList prints numbered white/black move pairs
+func printMoveList(pairs []pgn.MovePair) {
+	for _, pair := range pairs {
+		switch {
+		case pair.White == "":
+			fmt.Printf("%3d. ...     %s\n", pair.Number, pair.Black)
+		case pair.Black == "":
+			fmt.Printf("%3d. %s\n", pair.Number, pair.White)
+		default:
+			fmt.Printf("%3d. %-8s %s\n", pair.Number, pair.White, pair.Black)
+		}
+	}
+}
+
 func runUser(subcommand string, args []string) error {
 	switch subcommand {
 	case "add":
 		return runUserAdd(args)
+	case "import":
+		return runUserImport(args)
 	case "delete":
 		return runUserDelete(args)
 	case "set-password":
@@ -162,6 +556,23 @@ func runUser(subcommand string, args []string) error {
 	}
 }
 
+// argonFlags holds the Argon2id flags shared by commands that hash passwords
+type argonFlags struct {
+	memory, iterations, threads *uint
+}
+
+func passwordParamFlags(fs *flag.FlagSet) argonFlags {
+	return argonFlags{
+		memory:     fs.Uint("argon2-memory", auth.DefaultArgonMemory, "Argon2id memory cost in KiB"),
+		iterations: fs.Uint("argon2-iterations", auth.DefaultArgonTime, "Argon2id iterations"),
+		threads:    fs.Uint("argon2-parallelism", auth.DefaultArgonThreads, "Argon2id parallelism"),
+	}
+}
+
+func (a argonFlags) params() (service.PasswordParams, error) {
+	return service.NewPasswordParams(*a.memory, *a.iterations, *a.threads)
+}
+
 func runUserAdd(args []string) error {
 	fs := flag.NewFlagSet("user add", flag.ContinueOnError)
 	path := fs.String("path", "", "Database file path (required)")
@@ -171,6 +582,7 @@ func runUserAdd(args []string) error {
 	hash := fs.String("hash", "", "Pre-computed password hash (optional)")
 	interactive := fs.Bool("interactive", false, "Interactive password prompt")
 	temp := fs.Bool("temp", false, "Create as temporary user (24h TTL, default: permanent)")
+	argon := passwordParamFlags(fs)
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -179,17 +591,16 @@ func runUserAdd(args []string) error {
 	if *path == "" {
 		return fmt.Errorf("database path required")
 	}
-	if *username == "" {
-		return fmt.Errorf("username required")
+	params, err := argon.params()
+	if err != nil {
+		return err
 	}
 
-	// Validate password/hash options
-	if *password != "" && *hash != "" {
-		return fmt.Errorf("cannot specify both -password and -hash")
+	input := userInput{Username: *username, Email: *email, Password: *password, Hash: *hash}
+	if *temp {
+		input.Type = "temp"
 	}
 
-	var passwordHash string
-
 	if *interactive {
 		if *password != "" || *hash != "" {
 			return fmt.Errorf("cannot use -interactive with -password or -hash")
@@ -200,31 +611,16 @@ func runUserAdd(args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to read password: %w", err)
 		}
-		if len(pwBytes) < 8 {
-			return fmt.Errorf("password must be at least 8 characters")
-		}
-
-		// Hash password (Argon2)
-		passwordHash, err = auth.HashPassword(string(pwBytes))
-		if err != nil {
-			return fmt.Errorf("failed to hash password: %w", err)
-		}
-	} else if *hash != "" {
-		passwordHash = *hash
-	} else if *password != "" {
-		if len(*password) < 8 {
-			return fmt.Errorf("password must be at least 8 characters")
-		}
-		// Hash password (Argon2)
-		var err error
-		passwordHash, err = auth.HashPassword(*password)
-		if err != nil {
-			return fmt.Errorf("failed to hash password: %w", err)
-		}
-	} else {
+		input.Password = string(pwBytes)
+	} else if *password == "" && *hash == "" {
 		return fmt.Errorf("password required: use -password, -hash, or -interactive")
 	}
 
+	record, err := newUserRecord(input, params)
+	if err != nil {
+		return err
+	}
+
 	store, err := storage.NewStore(*path, false)
 	if err != nil {
 		return fmt.Errorf("failed to open store: %w", err)
@@ -232,10 +628,9 @@ func runUserAdd(args []string) error {
 	defer store.Close()
 
 	// Generate user ID with conflict check
-	var userID string
 	for attempts := 0; attempts < 10; attempts++ {
-		userID = uuid.New().String()
-		if _, err := store.GetUserByID(userID); err != nil {
+		record.UserID = uuid.New().String()
+		if _, err := store.GetUserByID(record.UserID); err != nil {
 			// User doesn't exist, ID is unique
 			break
 		}
@@ -244,31 +639,12 @@ func runUserAdd(args []string) error {
 		}
 	}
 
-	// Determine account type (CLI default = permanent)
-	accountType := "permanent"
-	var expiresAt *time.Time
-	if *temp {
-		accountType = "temp"
-		expiry := time.Now().UTC().Add(24 * time.Hour)
-		expiresAt = &expiry
-	}
-
-	record := storage.UserRecord{
-		UserID:       userID,
-		Username:     strings.ToLower(*username),
-		Email:        strings.ToLower(*email),
-		PasswordHash: passwordHash,
-		AccountType:  accountType,
-		CreatedAt:    time.Now().UTC(),
-		ExpiresAt:    expiresAt,
-	}
-
 	if err := store.CreateUser(record); err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
 	fmt.Printf("User created successfully:\n")
-	fmt.Printf("  ID: %s\n", userID)
+	fmt.Printf("  ID: %s\n", record.UserID)
 	fmt.Printf("  Username: %s\n", *username)
 	if *email != "" {
 		fmt.Printf("  Email: %s\n", *email)
@@ -276,6 +652,198 @@ func runUserAdd(args []string) error {
 	return nil
 }
 
+func runUserImport(args []string) error {
+	fs := flag.NewFlagSet("user import", flag.ContinueOnError)
+	path := fs.String("path", "", "Database file path (required)")
+	file := fs.String("file", "", "CSV with a header row, or .json array, of username,email,password,hash,type (required)")
+	dryRun := fs.Bool("dry-run", false, "Validate every row and report results without writing")
+	argon := passwordParamFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return fmt.Errorf("database path required")
+	}
+	if *file == "" {
+		return fmt.Errorf("import file required")
+	}
+	params, err := argon.params()
+	if err != nil {
+		return err
+	}
+
+	inputs, err := readUserImport(*file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *file, err)
+	}
+
+	// Validate and hash every row, invalid rows are reported and left out
+	var records []storage.UserRecord
+	var rows []int
+	failed := 0
+	for i, in := range inputs {
+		record, err := newUserRecord(in, params)
+		if err != nil {
+			fmt.Printf("  row %d (%s): FAILED: %v\n", i+1, in.Username, err)
+			failed++
+			continue
+		}
+		record.UserID = uuid.New().String()
+		records = append(records, record)
+		rows = append(rows, i+1)
+	}
+
+	store, err := storage.NewStore(*path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	defer store.Close()
+
+	created, err := store.ImportUsers(records, *dryRun)
+	if err != nil {
+		return fmt.Errorf("import failed, no users created: %w", err)
+	}
+
+	imported, skipped := 0, 0
+	for i, record := range records {
+		if created[i] {
+			fmt.Printf("  row %d (%s): ok\n", rows[i], record.Username)
+			imported++
+		} else {
+			fmt.Printf("  row %d (%s): skipped, username or email exists\n", rows[i], record.Username)
+			skipped++
+		}
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Dry run, would import"
+	}
+	fmt.Printf("\n%s %d user(s), skipped %d, failed %d\n", verb, imported, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d row(s) failed validation", failed)
+	}
+	return nil
+}
+
+// readUserImport loads users from a JSON array or a CSV file with a header row
+func readUserImport(path string) ([]userInput, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var inputs []userInput
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(f)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&inputs); err != nil {
+			return nil, err
+		}
+		return inputs, nil
+	}
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "username", "email", "password", "hash", "type":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("header has no username column")
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	for _, row := range rows[1:] {
+		inputs = append(inputs, userInput{
+			Username: field(row, "username"),
+			Email:    field(row, "email"),
+			Password: field(row, "password"),
+			Hash:     field(row, "hash"),
+			Type:     field(row, "type"),
+		})
+	}
+	return inputs, nil
+}
+
+// userInput is one user to create, from flags or an import file
+type userInput struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Hash     string `json:"hash"`
+	Type     string `json:"type"` // "permanent" (default) or "temp"
+}
+
+// newUserRecord validates a user and hashes its password, the caller assigns UserID
+func newUserRecord(in userInput, params service.PasswordParams) (storage.UserRecord, error) {
+	if in.Username == "" {
+		return storage.UserRecord{}, fmt.Errorf("username required")
+	}
+
+	// Validate password/hash options
+	var passwordHash string
+	switch {
+	case in.Password != "" && in.Hash != "":
+		return storage.UserRecord{}, fmt.Errorf("cannot specify both -password and -hash")
+	case in.Hash != "":
+		if err := auth.ValidatePHCHashFormat(in.Hash); err != nil {
+			return storage.UserRecord{}, fmt.Errorf("invalid hash format: %w", err)
+		}
+		passwordHash = in.Hash
+	case len(in.Password) < 8:
+		return storage.UserRecord{}, fmt.Errorf("password must be at least 8 characters")
+	default:
+		// Hash password (Argon2)
+		var err error
+		passwordHash, err = params.HashPassword(in.Password)
+		if err != nil {
+			return storage.UserRecord{}, fmt.Errorf("failed to hash password: %w", err)
+		}
+	}
+
+	// Determine account type (CLI default = permanent)
+	var expiresAt *time.Time
+	switch in.Type {
+	case "", "permanent":
+		in.Type = "permanent"
+	case "temp":
+		expiry := time.Now().UTC().Add(24 * time.Hour)
+		expiresAt = &expiry
+	default:
+		return storage.UserRecord{}, fmt.Errorf("unknown account type %q, expected permanent or temp", in.Type)
+	}
+
+	return storage.UserRecord{
+		Username:     strings.ToLower(in.Username),
+		Email:        strings.ToLower(in.Email),
+		PasswordHash: passwordHash,
+		AccountType:  in.Type,
+		CreatedAt:    time.Now().UTC(),
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
 func runUserDelete(args []string) error {
 	fs := flag.NewFlagSet("user delete", flag.ContinueOnError)
 	path := fs.String("path", "", "Database file path (required)")
@@ -327,6 +895,7 @@ func runUserSetPassword(args []string) error {
 	username := fs.String("username", "", "Username (required)")
 	password := fs.String("password", "", "New password")
 	interactive := fs.Bool("interactive", false, "Interactive password prompt")
+	argon := passwordParamFlags(fs)
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -338,6 +907,10 @@ func runUserSetPassword(args []string) error {
 	if *username == "" {
 		return fmt.Errorf("username required")
 	}
+	params, err := argon.params()
+	if err != nil {
+		return err
+	}
 
 	var newPassword string
 	if *interactive {
@@ -374,7 +947,7 @@ func runUserSetPassword(args []string) error {
 	}
 
 	// Hash password (Argon2)
-	passwordHash, err := auth.HashPassword(newPassword)
+	passwordHash, err := params.HashPassword(newPassword)
 	if err != nil {
 		return fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -518,6 +1091,7 @@ func runUserSetUsername(args []string) error {
 func runUserList(args []string) error {
 	fs := flag.NewFlagSet("user list", flag.ContinueOnError)
 	path := fs.String("path", "", "Database file path (required)")
+	asJSON := fs.Bool("json", false, "Print users as a JSON array (password hashes omitted)")
 
 	if err := fs.Parse(args); err != nil {
 		return err
@@ -538,6 +1112,10 @@ func runUserList(args []string) error {
 		return fmt.Errorf("failed to list users: %w", err)
 	}
 
+	if *asJSON {
+		return printJSON(users)
+	}
+
 	if len(users) == 0 {
 		fmt.Println("No users found")
 		return nil
@@ -8,17 +8,23 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"chess/cmd/chess-server/cli"
+	"chess/internal/config"
+	"chess/internal/server/engine"
 	"chess/internal/server/http"
 	"chess/internal/server/processor"
 	"chess/internal/server/service"
 	"chess/internal/server/storage"
 	"chess/internal/server/webserver"
+
+	"github.com/lixenwraith/auth"
 )
 
 const (
@@ -36,6 +42,8 @@ func main() {
 
 	// Command-line flags
 	var (
+		configPath = flag.String("config", "", "JSON file of flag values, e.g. {\"api-port\": 8080}; explicit flags take precedence")
+
 		// API server flags (renamed)
 		apiHost     = flag.String("api-host", "localhost", "API server host")
 		apiPort     = flag.Int("api-port", 8080, "API server port")
@@ -43,6 +51,50 @@ func main() {
 		storagePath = flag.String("storage-path", "", "Path to SQLite database file (disables persistence if empty)")
 		pidPath     = flag.String("pid", "", "Optional path to write PID file")
 		pidLock     = flag.Bool("pid-lock", false, "Lock PID file to allow only one instance (requires -pid)")
+		maxFENLen   = flag.Int("max-fen-length", processor.DefaultMaxFENLength, "Maximum FEN length accepted for new games (up to 256)")
+		deadDraw    = flag.Bool("dead-position-draw", false, "Draw computer versus computer games without mating material or with a long run of 0.00 evaluations")
+		deadPlies   = flag.Int("dead-draw-plies", 20, "Consecutive 0.00 engine evaluations that draw a computer game (with -dead-position-draw)")
+		deadClock   = flag.Int("dead-draw-halfmove", 40, "Halfmove clock the 0.00 evaluations also need (with -dead-position-draw)")
+		rejectOver  = flag.Bool("reject-finished-fen", false, "Reject new games from checkmate, stalemate or insufficient material positions instead of creating them already ended")
+		strictUndo  = flag.Bool("strict-undo", false, "Require \"force\": true to undo moves of a finished game")
+		strictPromo = flag.Bool("strict-promotion", true, "Reject pawn moves to the last rank without a promotion piece and promotion pieces on other moves, instead of promoting to a queen or dropping the piece")
+		cfgMove     = flag.Bool("configure-auto-move", true, "Start the computer's move when configuring players hands it the side to move")
+		reqClaim    = flag.Bool("require-claim", false, "Require authenticated slot owners for human moves (online play)")
+		syzygyPath  = flag.String("syzygy-path", "", "Syzygy tablebase directories for endgame play and analysis (empty disables)")
+		rateLimitDB = flag.Bool("persist-rate-limits", false, "Keep rate limit counters in storage so they survive restarts (requires -storage-path)")
+		maxConns    = flag.Int("max-ip-connections", http.DefaultMaxConnsPerIP, "Long-poll and search stream requests each client IP may hold open at once (0 for no limit)")
+		proxies     = flag.String("trusted-proxies", "", "Comma-separated reverse proxy addresses or CIDR ranges whose X-Forwarded-For identifies the client (empty uses connection addresses)")
+		logSkip     = flag.String("log-skip-paths", strings.Join(http.DefaultLogSkipPaths, ","), "Comma-separated request paths left out of the access log (empty logs all)")
+		enginePath  = flag.String("engine-path", "stockfish", "UCI engine binary, looked up in PATH unless it contains a separator")
+		engineWork  = flag.Int("engine-workers", processor.DefaultEngineWorkers, "Engines computing computer moves concurrently (1-16)")
+		maxDepth    = flag.Int("engine-max-depth", 0, "Search depth ceiling for every engine search, 0 for none (up to 99)")
+		budget      = flag.Duration("engine-budget", 0, "Engine time each game and each client IP may use per window (e.g. 2m, 0 disables)")
+		budgetWin   = flag.Duration("engine-budget-window", processor.DefaultEngineBudgetWindow, "Rolling window for -engine-budget")
+		posSync     = flag.Bool("engine-position-sync", true, "Wait for the engine to acknowledge each position before searching or reading its FEN")
+		nativeApply = flag.Bool("engine-native-apply", true, "Apply computer moves with the built-in board instead of a validation engine round trip")
+		affinity    = flag.Bool("engine-affinity", false, "Prefer the worker engine that searched a game's previous computer move")
+		stream      = flag.Bool("search-stream", false, "Stream depth and score of running computer move searches at /games/:gameId/search-stream")
+		queueLimit  = flag.Int("engine-queue-threshold", 0, "Queued computer moves above which /health reports the engine queue degraded once sustained (0-100, 0 disables)")
+		queueHold   = flag.Duration("engine-queue-sustain", processor.DefaultQueueSustain, "How long the queue must stay above -engine-queue-threshold to report degraded")
+		queueReject = flag.Int("engine-queue-reject", 0, "Queued computer moves above which new computer games and computer moves get 503 (0-100, 0 disables)")
+		warmup      = flag.Bool("engine-warmup", false, "Report /health as warming (503) until every engine worker answered isready")
+		fenFallback = flag.Bool("engine-fen-fallback", true, "Compute positions natively when the engine times out reporting a FEN, instead of rejecting the move")
+		retries     = flag.Int("engine-validation-retries", processor.DefaultValidationRetries, "Retries after a failed or empty validation engine reply (0-5)")
+		evalCache   = flag.Int("eval-cache-size", processor.DefaultEvalCacheSize, "Engine evaluations cached by position for game end checks, analysis and hints (0 disables)")
+		evalTTL     = flag.Duration("eval-cache-ttl", 0, "Expire cached evaluations after this duration (e.g. 1h, 0 keeps them until evicted)")
+		engineOpts  = flag.String("engine-options", "", "Comma-separated UCI option names players may set via engineOptions (e.g. \"Hash,UCI_ShowWDL\")")
+
+		// Password hashing flags
+		argonMemory     = flag.Uint("argon2-memory", auth.DefaultArgonMemory, "Argon2id memory cost in KiB for password hashes")
+		argonIterations = flag.Uint("argon2-iterations", auth.DefaultArgonTime, "Argon2id iterations for password hashes")
+		argonThreads    = flag.Uint("argon2-parallelism", auth.DefaultArgonThreads, "Argon2id parallelism for password hashes")
+
+		// Data retention flags
+		gameRetention   = flag.Duration("game-retention", 0, "Delete finished games older than this duration (e.g. 720h, 0 disables)")
+		retentionDryRun = flag.Bool("game-retention-dry-run", false, "Log finished games eligible for retention cleanup without deleting")
+		maxGames        = flag.Int("max-games", 0, "Games held in memory before new games are refused (0 unlimited)")
+		evictFinished   = flag.Bool("evict-finished-games", false, "Evict the longest unchanged finished game when -max-games is reached")
+		keepUndo        = flag.Bool("keep-undo-history", false, "Record moves taken back by undo or reset in the undo log instead of deleting them")
 
 		// Web UI server flags
 		serve   = flag.Bool("serve", false, "Enable web UI server")
@@ -51,10 +103,68 @@ func main() {
 	)
 	flag.Parse()
 
+	if *configPath != "" {
+		if err := config.Apply(flag.CommandLine, *configPath); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
 	// Validate PID flags
 	if *pidLock && *pidPath == "" {
 		log.Fatal("Error: -pid-lock flag requires the -pid flag to be set")
 	}
+	if *maxConns < 0 {
+		log.Fatal("Error: -max-ip-connections must not be negative")
+	}
+	var trustedProxies []string
+	for _, proxy := range strings.Split(*proxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			log.Fatalf("Error: -trusted-proxies entry %q is not an IP address or CIDR range", proxy)
+		}
+		trustedProxies = append(trustedProxies, proxy)
+	}
+	if *enginePath == "" {
+		log.Fatal("Error: -engine-path must not be empty")
+	}
+	if *engineWork < 1 || *engineWork > 16 {
+		log.Fatal("Error: -engine-workers must be between 1 and 16")
+	}
+	if *maxDepth < 0 || *maxDepth > 99 {
+		log.Fatal("Error: -engine-max-depth must be between 0 and 99")
+	}
+	if *queueLimit < 0 || *queueLimit > 100 {
+		log.Fatal("Error: -engine-queue-threshold must be between 0 and 100")
+	}
+	if *queueReject < 0 || *queueReject > 100 {
+		log.Fatal("Error: -engine-queue-reject must be between 0 and 100")
+	}
+	if *queueHold < 0 {
+		log.Fatal("Error: -engine-queue-sustain must not be negative")
+	}
+	if *retries < 0 || *retries > 5 {
+		log.Fatal("Error: -engine-validation-retries must be between 0 and 5")
+	}
+	if *deadPlies < 1 {
+		log.Fatal("Error: -dead-draw-plies must be positive")
+	}
+	if *deadClock < 0 || *deadClock > 100 {
+		log.Fatal("Error: -dead-draw-halfmove must be between 0 and 100")
+	}
+	if *maxGames < 0 {
+		log.Fatal("Error: -max-games must not be negative")
+	}
+	if *maxFENLen < 1 || *maxFENLen > 256 {
+		log.Fatal("Error: -max-fen-length must be between 1 and 256")
+	}
+	if *budget < 0 {
+		log.Fatal("Error: -engine-budget must not be negative")
+	}
+	if *budget > 0 && *budgetWin <= 0 {
+		log.Fatal("Error: -engine-budget-window must be positive")
+	}
 
 	// Manage PID file if requested
 	if *pidPath != "" {
@@ -102,22 +212,113 @@ func main() {
 		log.Printf("JWT secret generated (sessions valid until restart)")
 	}
 
+	passwordParams, err := service.NewPasswordParams(*argonMemory, *argonIterations, *argonThreads)
+	if err != nil {
+		log.Fatalf("Invalid password hashing parameters: %v", err)
+	}
+
 	// 2. Initialize the Service with optional storage and auth
-	svc := service.New(store, jwtSecret)
+	svc, err := service.New(service.Options{
+		Store:          store,
+		JWTSecret:      jwtSecret,
+		PasswordParams: &passwordParams,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize service: %v", err)
+	}
+
+	if *gameRetention > 0 {
+		if store == nil {
+			log.Printf("Warning: -game-retention ignored, storage disabled")
+		} else {
+			svc.SetGameRetention(*gameRetention, *retentionDryRun)
+			log.Printf("Game retention: %s (dry-run: %v)", *gameRetention, *retentionDryRun)
+		}
+	}
+
+	if *maxGames > 0 {
+		svc.SetGameCapacity(*maxGames, *evictFinished)
+		log.Printf("Game capacity: %d (evict finished: %v)", *maxGames, *evictFinished)
+	}
+
+	if *keepUndo {
+		if store == nil {
+			log.Printf("Warning: -keep-undo-history ignored, storage disabled")
+		} else {
+			svc.SetKeepUndoHistory(true)
+			log.Printf("Undo history: kept in undo log")
+		}
+	}
 
 	// Start cleanup job for expired users/sessions
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
 	go svc.RunCleanupJob(cleanupCtx, service.CleanupJobInterval)
 
 	// 3. Initialize the Processor (Orchestrator), injecting the service
-	proc, err := processor.New(svc)
+	engine.SetPath(*enginePath)
+	engine.SetPositionSync(*posSync)
+	proc, err := processor.New(svc, *engineWork)
 	if err != nil {
 		svc.Shutdown(gracefulShutdownTimeout)
 		log.Fatalf("Failed to initialize processor: %v", err)
 	}
+	proc.SetMaxFENLength(*maxFENLen)
+	proc.SetRequireClaim(*reqClaim)
+	proc.SetRejectFinishedPositions(*rejectOver)
+	if *deadDraw {
+		proc.SetDeadPositionDraw(*deadPlies, *deadClock)
+		log.Printf("Dead position draws: %d level evaluations at halfmove clock %d", *deadPlies, *deadClock)
+	}
+	proc.SetStrictUndo(*strictUndo)
+	proc.SetStrictPromotion(*strictPromo)
+	proc.SetConfigureAutoMove(*cfgMove)
+	proc.SetFENFallback(*fenFallback)
+	proc.SetNativeApply(*nativeApply)
+	proc.SetEngineWarmup(*warmup)
+	proc.SetQueueSaturation(*queueLimit, *queueHold)
+	proc.SetQueueReject(*queueReject)
+	proc.SetEngineAffinity(*affinity)
+	proc.SetSearchStream(*stream)
+	proc.SetValidationRetries(*retries)
+	proc.SetEvalCache(*evalCache, *evalTTL)
+	if *maxDepth > 0 {
+		proc.SetMaxDepth(*maxDepth)
+		log.Printf("Engine depth ceiling: %d", *maxDepth)
+	}
+	if *budget > 0 {
+		proc.SetEngineBudget(*budget, *budgetWin)
+		log.Printf("Engine time budget: %v per %v per game and client", *budget, *budgetWin)
+	}
+	if *syzygyPath != "" {
+		if _, err := os.Stat(strings.Split(*syzygyPath, string(os.PathListSeparator))[0]); err != nil {
+			log.Printf("Warning: -syzygy-path ignored, tablebases unavailable: %v", err)
+		} else {
+			proc.SetSyzygyPath(*syzygyPath)
+			log.Printf("Syzygy tablebases: %s", *syzygyPath)
+		}
+	}
+	if *engineOpts != "" {
+		proc.SetEngineOptionAllowlist(strings.Split(*engineOpts, ","))
+		log.Printf("Engine options allowed: %s", *engineOpts)
+	}
 
 	// 4. Initialize the Fiber App/HTTP Handler, injecting processor and service
-	app := http.NewFiberApp(proc, svc, *dev)
+	var limiterStore *storage.Store
+	if *rateLimitDB {
+		if store == nil {
+			log.Printf("Warning: -persist-rate-limits ignored, storage disabled")
+		} else {
+			limiterStore = store
+			log.Printf("Rate limit counters: persisted in storage")
+		}
+	}
+	var logSkipPaths []string
+	for _, path := range strings.Split(*logSkip, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			logSkipPaths = append(logSkipPaths, path)
+		}
+	}
+	app := http.NewFiberApp(proc, svc, *dev, limiterStore, logSkipPaths, *maxConns, trustedProxies)
 
 	// API Server configuration
 	apiAddr := fmt.Sprintf("%s:%d", *apiHost, *apiPort)
@@ -126,7 +327,8 @@ func main() {
 	go func() {
 		log.Printf("Chess API Server starting...")
 		log.Printf("API Listening on: http://%s", apiAddr)
-		log.Printf("API Version: v1")
+		log.Printf("Server Version: %s", http.Version)
+		log.Printf("API Version: %s", http.APIVersion)
 		log.Printf("Authentication: Enabled (JWT)")
 		if *dev {
 			log.Printf("Rate Limit: 20 requests/second per IP (DEV MODE)")
@@ -193,4 +395,3 @@ func main() {
 
 	log.Println("Servers exited")
 }
-
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,21 +13,31 @@ import (
 	"chess/internal/client/display"
 )
 
-const HttpTimeout = 30 * time.Second
+// Request timeouts, applied per request rather than on the HTTP client
+const (
+	HttpTimeout     = 30 * time.Second // Default for regular requests
+	HealthTimeout   = 5 * time.Second
+	LongPollTimeout = 45 * time.Second // Outlasts the server's 30s wait plus processing
+	AnalysisTimeout = 40 * time.Second // Full game analysis runs synchronously on the server
+)
 
 type Client struct {
 	BaseURL    string
 	AuthToken  string
 	HTTPClient *http.Client
 	Verbose    bool
+	Timeout    time.Duration // Default request timeout
 }
 
-func New(baseURL string) *Client {
+// New creates a client, timeout 0 uses HttpTimeout
+func New(baseURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = HttpTimeout
+	}
 	return &Client{
-		BaseURL: baseURL,
-		HTTPClient: &http.Client{
-			Timeout: HttpTimeout,
-		},
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{},
+		Timeout:    timeout,
 	}
 }
 
@@ -43,7 +54,18 @@ func (c *Client) SetToken(token string) {
 	c.AuthToken = token
 }
 
-func (c *Client) doRequest(method, path string, body any, result any) error {
+// SetTimeout changes the default request timeout
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.Timeout = timeout
+}
+
+func (c *Client) doRequest(ctx context.Context, method, path string, body any, result any) error {
+	return c.doRequestTimeout(ctx, c.Timeout, method, path, body, result)
+}
+
+// doRequestTimeout bounds the request by timeout, or by an earlier deadline
+// or cancellation of ctx
+func (c *Client) doRequestTimeout(ctx context.Context, timeout time.Duration, method, path string, body any, result any) error {
 	url := c.BaseURL + path
 
 	// Prepare body
@@ -58,8 +80,11 @@ func (c *Client) doRequest(method, path string, body any, result any) error {
 		bodyStr = string(jsonData)
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Create request
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return err
 	}
@@ -106,7 +131,7 @@ func (c *Client) doRequest(method, path string, body any, result any) error {
 	if resp.StatusCode >= 400 {
 		statusColor = display.Red
 	}
-	fmt.Printf("%s[%d %s]%s\n", statusColor, resp.StatusCode, http.StatusText(resp.StatusCode), display.Reset)
+	fmt.Println(display.C(statusColor, fmt.Sprintf("[%d %s]", resp.StatusCode, http.StatusText(resp.StatusCode))))
 
 	// Display response body if verbose
 	if c.Verbose && len(respBody) > 0 {
@@ -123,8 +148,10 @@ func (c *Client) doRequest(method, path string, body any, result any) error {
 
 	// Parse error response
 	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
 		var errResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errResp); err == nil {
+			apiErr.Code, apiErr.Message, apiErr.Details = errResp.Code, errResp.Error, errResp.Details
 			if !c.Verbose {
 				display.Print(display.Red, "Error: %s\n", errResp.Error)
 				if errResp.Code != "" {
@@ -137,7 +164,7 @@ func (c *Client) doRequest(method, path string, body any, result any) error {
 		} else if !c.Verbose {
 			display.Println(display.Red, string(respBody))
 		}
-		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+		return apiErr
 	}
 
 	// Parse success response
@@ -154,84 +181,171 @@ func (c *Client) doRequest(method, path string, body any, result any) error {
 }
 
 // API Methods
+// Each method has a Context variant whose context cancels the request or
+// shortens its deadline, the plain method uses context.Background()
 
 func (c *Client) Health() (*HealthResponse, error) {
+	return c.HealthContext(context.Background())
+}
+
+func (c *Client) HealthContext(ctx context.Context) (*HealthResponse, error) {
 	var resp HealthResponse
-	err := c.doRequest("GET", "/health", nil, &resp)
+	err := c.doRequestTimeout(ctx, HealthTimeout, "GET", "/health", nil, &resp)
 	return &resp, err
 }
 
 func (c *Client) CreateGame(req *CreateGameRequest) (*GameResponse, error) {
+	return c.CreateGameContext(context.Background(), req)
+}
+
+func (c *Client) CreateGameContext(ctx context.Context, req *CreateGameRequest) (*GameResponse, error) {
+	var resp GameResponse
+	err := c.doRequest(ctx, "POST", "/api/v1/games", req, &resp)
+	return &resp, err
+}
+
+// CreateGameWaitFirstMove creates a game whose computer moves first and returns
+// it after that move, or still pending if the move outlasts the server's wait
+func (c *Client) CreateGameWaitFirstMove(req *CreateGameRequest) (*GameResponse, error) {
+	return c.CreateGameWaitFirstMoveContext(context.Background(), req)
+}
+
+func (c *Client) CreateGameWaitFirstMoveContext(ctx context.Context, req *CreateGameRequest) (*GameResponse, error) {
 	var resp GameResponse
-	err := c.doRequest("POST", "/api/v1/games", req, &resp)
+	err := c.doRequestTimeout(ctx, LongPollTimeout, "POST", "/api/v1/games?waitFirstMove=true", req, &resp)
 	return &resp, err
 }
 
 func (c *Client) GetGame(gameID string) (*GameResponse, error) {
+	return c.GetGameContext(context.Background(), gameID)
+}
+
+func (c *Client) GetGameContext(ctx context.Context, gameID string) (*GameResponse, error) {
 	var resp GameResponse
-	err := c.doRequest("GET", "/api/v1/games/"+gameID, nil, &resp)
+	err := c.doRequest(ctx, "GET", "/api/v1/games/"+gameID, nil, &resp)
 	return &resp, err
 }
 
 func (c *Client) GetGameWithPoll(gameID string, moveCount int) (*GameResponse, error) {
+	return c.GetGameWithPollContext(context.Background(), gameID, moveCount)
+}
+
+func (c *Client) GetGameWithPollContext(ctx context.Context, gameID string, moveCount int) (*GameResponse, error) {
 	var resp GameResponse
 	path := fmt.Sprintf("/api/v1/games/%s?wait=true&moveCount=%d", gameID, moveCount)
-	err := c.doRequest("GET", path, nil, &resp)
+	err := c.doRequestTimeout(ctx, LongPollTimeout, "GET", path, nil, &resp)
 	return &resp, err
 }
 
 func (c *Client) DeleteGame(gameID string) error {
-	return c.doRequest("DELETE", "/api/v1/games/"+gameID, nil, nil)
+	return c.DeleteGameContext(context.Background(), gameID)
+}
+
+func (c *Client) DeleteGameContext(ctx context.Context, gameID string) error {
+	return c.doRequest(ctx, "DELETE", "/api/v1/games/"+gameID, nil, nil)
 }
 
 func (c *Client) MakeMove(gameID string, move string) (*GameResponse, error) {
+	return c.MakeMoveContext(context.Background(), gameID, move)
+}
+
+func (c *Client) MakeMoveContext(ctx context.Context, gameID string, move string) (*GameResponse, error) {
 	req := &MoveRequest{Move: move}
 	var resp GameResponse
-	err := c.doRequest("POST", "/api/v1/games/"+gameID+"/moves", req, &resp)
+	err := c.doRequest(ctx, "POST", "/api/v1/games/"+gameID+"/moves", req, &resp)
 	return &resp, err
 }
 
 func (c *Client) UndoMoves(gameID string, count int) (*GameResponse, error) {
+	return c.UndoMovesContext(context.Background(), gameID, count)
+}
+
+func (c *Client) UndoMovesContext(ctx context.Context, gameID string, count int) (*GameResponse, error) {
 	req := &UndoRequest{Count: count}
 	var resp GameResponse
-	err := c.doRequest("POST", "/api/v1/games/"+gameID+"/undo", req, &resp)
+	err := c.doRequest(ctx, "POST", "/api/v1/games/"+gameID+"/undo", req, &resp)
 	return &resp, err
 }
 
+// GetBoard fetches the ASCII board along with the check and game over status
 func (c *Client) GetBoard(gameID string) (*BoardResponse, error) {
+	return c.GetBoardContext(context.Background(), gameID)
+}
+
+func (c *Client) GetBoardContext(ctx context.Context, gameID string) (*BoardResponse, error) {
 	var resp BoardResponse
-	err := c.doRequest("GET", "/api/v1/games/"+gameID+"/board", nil, &resp)
+	err := c.doRequest(ctx, "GET", "/api/v1/games/"+gameID+"/board?status=true", nil, &resp)
+	return &resp, err
+}
+
+// Analyze requests a per-move evaluation, depth 0 uses the server default
+func (c *Client) Analyze(gameID string, depth int) (*AnalysisResponse, error) {
+	return c.AnalyzeContext(context.Background(), gameID, depth)
+}
+
+func (c *Client) AnalyzeContext(ctx context.Context, gameID string, depth int) (*AnalysisResponse, error) {
+	var resp AnalysisResponse
+	path := fmt.Sprintf("/api/v1/games/%s/analysis?depth=%d", gameID, depth)
+	err := c.doRequestTimeout(ctx, AnalysisTimeout, "GET", path, nil, &resp)
+	return &resp, err
+}
+
+// Hint requests a suggested move for the side to move at a skill level (0-20)
+func (c *Client) Hint(gameID string, strength int) (*MoveInfo, error) {
+	return c.HintContext(context.Background(), gameID, strength)
+}
+
+func (c *Client) HintContext(ctx context.Context, gameID string, strength int) (*MoveInfo, error) {
+	var resp MoveInfo
+	path := fmt.Sprintf("/api/v1/games/%s/hint?strength=%d", gameID, strength)
+	err := c.doRequest(ctx, "GET", path, nil, &resp)
 	return &resp, err
 }
 
 func (c *Client) Register(username, password, email string) (*AuthResponse, error) {
+	return c.RegisterContext(context.Background(), username, password, email)
+}
+
+func (c *Client) RegisterContext(ctx context.Context, username, password, email string) (*AuthResponse, error) {
 	req := &RegisterRequest{
 		Username: username,
 		Password: password,
 		Email:    email,
 	}
 	var resp AuthResponse
-	err := c.doRequest("POST", "/api/v1/auth/register", req, &resp)
+	err := c.doRequest(ctx, "POST", "/api/v1/auth/register", req, &resp)
 	return &resp, err
 }
 
 func (c *Client) Login(identifier, password string) (*AuthResponse, error) {
+	return c.LoginContext(context.Background(), identifier, password)
+}
+
+func (c *Client) LoginContext(ctx context.Context, identifier, password string) (*AuthResponse, error) {
 	req := &LoginRequest{
 		Identifier: identifier,
 		Password:   password,
 	}
 	var resp AuthResponse
-	err := c.doRequest("POST", "/api/v1/auth/login", req, &resp)
+	err := c.doRequest(ctx, "POST", "/api/v1/auth/login", req, &resp)
 	return &resp, err
 }
 
 func (c *Client) Logout() error {
-	return c.doRequest("POST", "/api/v1/auth/logout", nil, nil)
+	return c.LogoutContext(context.Background())
+}
+
+func (c *Client) LogoutContext(ctx context.Context) error {
+	return c.doRequest(ctx, "POST", "/api/v1/auth/logout", nil, nil)
 }
 
 func (c *Client) GetCurrentUser() (*UserResponse, error) {
+	return c.GetCurrentUserContext(context.Background())
+}
+
+func (c *Client) GetCurrentUserContext(ctx context.Context) (*UserResponse, error) {
 	var resp UserResponse
-	err := c.doRequest("GET", "/api/v1/auth/me", nil, &resp)
+	err := c.doRequest(ctx, "GET", "/api/v1/auth/me", nil, &resp)
 	return &resp, err
 }
 
@@ -245,5 +359,9 @@ func (c *Client) RawRequest(method, path string, body string) error {
 		}
 	}
 
-	return c.doRequest(method, path, bodyData, nil)
+	timeout := c.Timeout
+	if strings.Contains(path, "wait=true") {
+		timeout = LongPollTimeout
+	}
+	return c.doRequestTimeout(context.Background(), timeout, method, path, bodyData, nil)
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error codes reported by the server in ErrorResponse.Code
+const (
+	ErrGameNotFound      = "GAME_NOT_FOUND"
+	ErrInvalidMove       = "INVALID_MOVE"
+	ErrNotHumanTurn      = "NOT_HUMAN_TURN"
+	ErrGameOver          = "GAME_OVER"
+	ErrRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
+	ErrInvalidContent    = "INVALID_CONTENT_TYPE"
+	ErrInvalidRequest    = "INVALID_REQUEST"
+	ErrInvalidFEN        = "INVALID_FEN"
+	ErrInternalError     = "INTERNAL_ERROR"
+	ErrResourceLimit     = "RESOURCE_LIMIT"
+	ErrUnauthorized      = "UNAUTHORIZED"
+	ErrMoveConflict      = "MOVE_CONFLICT"
+	ErrEngineSaturated   = "ENGINE_SATURATED"
+)
+
+// APIError is returned for responses with an error status, Code is empty
+// when the body was not an ErrorResponse
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = fmt.Sprintf("request failed with status %d", e.StatusCode)
+	}
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Details != "" {
+		msg += ": " + e.Details
+	}
+	return msg
+}
+
+// ErrorCode returns the server error code carried by err, empty if err is not an APIError
+func ErrorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
@@ -36,13 +36,14 @@ type LoginRequest struct {
 
 // Response types
 type GameResponse struct {
-	GameID   string          `json:"gameId"`
-	FEN      string          `json:"fen"`
-	Turn     string          `json:"turn"`
-	State    string          `json:"state"`
-	Moves    []string        `json:"moves"`
-	Players  PlayersResponse `json:"players"`
-	LastMove *MoveInfo       `json:"lastMove,omitempty"`
+	GameID     string          `json:"gameId"`
+	FEN        string          `json:"fen"`
+	InitialFEN string          `json:"initialFen"`
+	Turn       string          `json:"turn"`
+	State      string          `json:"state"`
+	Moves      []string        `json:"moves"`
+	Players    PlayersResponse `json:"players"`
+	LastMove   *MoveInfo       `json:"lastMove,omitempty"`
 }
 
 type PlayersResponse struct {
@@ -51,10 +52,11 @@ type PlayersResponse struct {
 }
 
 type PlayerInfo struct {
-	ID         string `json:"id"`
-	Type       int    `json:"type"`
-	Level      int    `json:"level,omitempty"`
-	SearchTime int    `json:"searchTime,omitempty"`
+	ID                  string `json:"id"`
+	Type                int    `json:"type"`
+	Level               int    `json:"level,omitempty"`
+	SearchTime          int    `json:"searchTime,omitempty"`
+	EffectiveSearchTime int    `json:"effectiveSearchTime,omitempty"` // After the server-side handicap
 }
 
 type MoveInfo struct {
@@ -64,9 +66,28 @@ type MoveInfo struct {
 	Depth       int    `json:"depth,omitempty"`
 }
 
+type AnalysisResponse struct {
+	GameID string        `json:"gameId"`
+	Depth  int           `json:"depth"`
+	Plies  []PlyAnalysis `json:"plies"`
+}
+
+type PlyAnalysis struct {
+	Ply         int    `json:"ply"`
+	Move        string `json:"move"`
+	PlayerColor string `json:"playerColor"`
+	BestMove    string `json:"bestMove"`
+	Score       int    `json:"score"`
+	MateIn      int    `json:"mateIn,omitempty"`
+	Loss        int    `json:"loss"`
+}
+
 type BoardResponse struct {
-	FEN   string `json:"fen"`
-	Board string `json:"board"`
+	FEN      string `json:"fen"`
+	Board    string `json:"board"`
+	InCheck  bool   `json:"inCheck"`
+	GameOver bool   `json:"gameOver"`
+	Reason   string `json:"reason,omitempty"`
 }
 
 type AuthResponse struct {
@@ -0,0 +1,236 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"chess/internal/client/api"
+	"chess/internal/client/display"
+	"chess/internal/client/session"
+	"chess/internal/server/board"
+	"chess/internal/server/core"
+	"chess/internal/server/pgn"
+)
+
+const (
+	summaryDepth     = 8   // Quick pass, deeper analysis is available from the API
+	summarySwings    = 3   // Number of largest swings listed
+	mistakeThreshold = 100 // Centipawns lost to count as a mistake
+	blunderThreshold = 300 // Centipawns lost to count as a blunder
+)
+
+func (r *Registry) registerAnalysisCommands() {
+	r.Register(&Command{
+		Name:        "analyze",
+		ShortName:   "a",
+		Description: "Evaluate every move of the current game",
+		Usage:       "analyze [depth]",
+		Handler:     analyzeHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "hint",
+		ShortName:   "k",
+		Description: "Suggest a move for the side to move",
+		Usage:       "hint [strength 0-20]",
+		Handler:     hintHandler,
+	})
+}
+
+func analyzeHandler(s *session.Session, args []string) error {
+	gameID := s.GetCurrentGame()
+	if gameID == "" {
+		return fmt.Errorf("no current game, use 'new' or 'join <gameId>'")
+	}
+
+	depth := 0 // Server default
+	if len(args) > 0 {
+		var err error
+		if depth, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("invalid depth: %s", args[0])
+		}
+	}
+
+	c := s.GetClient().(*api.Client)
+	game, err := c.GetGame(gameID)
+	if err != nil {
+		return err
+	}
+
+	display.Println(display.Magenta, "Analyzing %d moves...", len(game.Moves))
+	analysis, err := c.Analyze(gameID, depth)
+	if err != nil {
+		return err
+	}
+
+	plies, _ := pgn.Replay(game.InitialFEN, game.Moves)
+	display.Println(display.Cyan, "Analysis (depth %d):", analysis.Depth)
+	for i, ply := range analysis.Plies {
+		move := ply.Move
+		if i < len(plies) {
+			move = plies[i].SAN
+		}
+
+		eval := fmt.Sprintf("%+.2f", float64(ply.Score)/100)
+		if ply.MateIn != 0 {
+			eval = fmt.Sprintf("#%d", ply.MateIn)
+		}
+
+		line := fmt.Sprintf("  %3d. %s %-7s %7s", ply.Ply, ply.PlayerColor, move, eval)
+		switch {
+		case ply.Loss >= blunderThreshold:
+			fmt.Printf("%s  %s\n", line, display.C(display.Red, fmt.Sprintf("blunder (-%d)", ply.Loss)))
+		case ply.Loss >= mistakeThreshold:
+			fmt.Printf("%s  %s\n", line, display.C(display.Yellow, fmt.Sprintf("mistake (-%d)", ply.Loss)))
+		default:
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+func hintHandler(s *session.Session, args []string) error {
+	gameID := s.GetCurrentGame()
+	if gameID == "" {
+		return fmt.Errorf("no current game, use 'new' or 'join <gameId>'")
+	}
+
+	strength := 20
+	if len(args) > 0 {
+		var err error
+		if strength, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("invalid strength: %s", args[0])
+		}
+	}
+
+	c := s.GetClient().(*api.Client)
+	hint, err := c.Hint(gameID, strength)
+	if err != nil {
+		return err
+	}
+
+	move := hint.Move
+	if game := s.CurrentGameState; game != nil && game.GameID == gameID {
+		if san, err := toSAN(game.InitialFEN, game.Moves, hint.Move); err == nil {
+			move = fmt.Sprintf("%s (%s)", san, hint.Move)
+		}
+	}
+
+	display.Print(display.Magenta, "Hint: %s", move)
+	if hint.Depth > 0 {
+		fmt.Printf(" (depth %d, score %d)", hint.Depth, hint.Score)
+	}
+	fmt.Println()
+	return nil
+}
+
+// announceGameEnd prints the result of a finished game, followed by a move
+// analysis in verbose mode; returns false if the game is still in progress
+func announceGameEnd(s *session.Session, game *api.GameResponse) bool {
+	switch game.State {
+	case "white wins":
+		display.Println(display.Green, "\nCHECKMATE! White wins!")
+	case "black wins":
+		display.Println(display.Green, "\nCHECKMATE! Black wins!")
+	case "stalemate":
+		display.Println(display.Yellow, "\nSTALEMATE! Game drawn.")
+	case "draw":
+		display.Println(display.Yellow, "\nDRAW! Game drawn.")
+	case "aborted":
+		display.Println(display.Yellow, "\nGAME ABORTED. No result.")
+	default:
+		return false
+	}
+
+	if s.IsVerbose() {
+		printGameSummary(s, game)
+	}
+	return true
+}
+
+// printGameSummary lists move count, result and the largest evaluation swings
+func printGameSummary(s *session.Session, game *api.GameResponse) {
+	display.Println(display.Cyan, "\nGame Summary:")
+	fmt.Printf("  Moves:  %d (%d plies)\n", (len(game.Moves)+1)/2, len(game.Moves))
+	fmt.Printf("  Result: %s\n", resultForState(game.State))
+
+	if len(game.Moves) == 0 {
+		return
+	}
+
+	display.Println(display.Magenta, "Analyzing moves at depth %d...", summaryDepth)
+	c := s.GetClient().(*api.Client)
+	analysis, err := c.Analyze(game.GameID, summaryDepth)
+	if err != nil {
+		display.Println(display.Yellow, "Analysis unavailable: %s", err.Error())
+		return
+	}
+
+	// SAN needs the position before each move, replay up to any failure
+	plies, _ := pgn.Replay(game.InitialFEN, game.Moves)
+	san := func(i int) string {
+		if i < len(plies) {
+			return plies[i].SAN
+		}
+		return game.Moves[i]
+	}
+
+	// Number moves from the initial position, which may not be the standard start
+	startNumber, blackFirst := 1, false
+	if b, err := board.ParseFEN(game.InitialFEN); err == nil {
+		startNumber, blackFirst = b.FullMove(), b.Turn() == core.ColorBlack
+	}
+
+	swings := make([]api.PlyAnalysis, 0, len(analysis.Plies))
+	for _, ply := range analysis.Plies {
+		if ply.Loss >= mistakeThreshold {
+			swings = append(swings, ply)
+		}
+	}
+	if len(swings) == 0 {
+		display.Println(display.Green, "  No mistakes found")
+		return
+	}
+
+	sort.SliceStable(swings, func(i, j int) bool {
+		return swings[i].Loss > swings[j].Loss
+	})
+	if len(swings) > summarySwings {
+		swings = swings[:summarySwings]
+	}
+
+	fmt.Println("  Largest swings:")
+	for _, ply := range swings {
+		label, color := "mistake", display.Yellow
+		if ply.Loss >= blunderThreshold {
+			label, color = "blunder", display.Red
+		}
+
+		index := ply.Ply - 1
+		if blackFirst {
+			index++
+		}
+		number := fmt.Sprintf("%d.", startNumber+index/2)
+		if ply.PlayerColor == "b" {
+			number += ".."
+		}
+
+		best := ply.BestMove
+		if b, err := toSAN(game.InitialFEN, game.Moves[:ply.Ply-1], ply.BestMove); err == nil {
+			best = b
+		}
+
+		fmt.Printf("    %s %-7s %s (-%d), best was %s\n",
+			number, san(ply.Ply-1), display.C(color, label), ply.Loss, best)
+	}
+}
+
+// toSAN converts a UCI move to SAN in the position after the given moves
+func toSAN(initialFEN string, moves []string, uci string) (string, error) {
+	plies, err := pgn.Replay(initialFEN, append(moves[:len(moves):len(moves)], uci))
+	if err != nil {
+		return "", err
+	}
+	return plies[len(plies)-1].SAN, nil
+}
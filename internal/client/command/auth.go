@@ -61,7 +61,7 @@ func registerHandler(s *session.Session, args []string) error {
 	scanner.Scan()
 	username := strings.TrimSpace(scanner.Text())
 
-	password, err := readPassword(display.Yellow + "Password: " + display.Reset)
+	password, err := readPassword(display.C(display.Yellow, "Password: "))
 	if err != nil {
 		return err
 	}
@@ -95,7 +95,7 @@ func loginHandler(s *session.Session, args []string) error {
 	scanner.Scan()
 	identifier := strings.TrimSpace(scanner.Text())
 
-	password, err := readPassword(display.Yellow + "Password: " + display.Reset)
+	password, err := readPassword(display.C(display.Yellow, "Password: "))
 	if err != nil {
 		return err
 	}
@@ -27,6 +27,30 @@ func (r *Registry) registerDebugCommands() {
 		Handler:     urlHandler,
 	})
 
+	r.Register(&Command{
+		Name:        "theme",
+		ShortName:   "t",
+		Description: "List or select board color theme",
+		Usage:       "theme [name]",
+		Handler:     themeHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "style",
+		ShortName:   "y",
+		Description: "List or select board style",
+		Usage:       "style [ascii|box|compact]",
+		Handler:     styleHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "flip",
+		ShortName:   "b",
+		Description: "Toggle board orientation",
+		Usage:       "flip",
+		Handler:     flipHandler,
+	})
+
 	r.Register(&Command{
 		Name:        "raw",
 		ShortName:   ":",
@@ -74,6 +98,61 @@ func urlHandler(s *session.Session, args []string) error {
 	return nil
 }
 
+func themeHandler(s *session.Session, args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("Color mode: %s\n", display.Mode())
+		for _, name := range display.ThemeNames() {
+			marker := "  "
+			if name == display.CurrentTheme() {
+				marker = "* "
+			}
+			fmt.Printf("%s%s\n", marker, name)
+		}
+		return nil
+	}
+
+	if display.Mode() == display.ModeOff && args[0] != "off" {
+		return fmt.Errorf("terminal has no color support (set TERM or COLORTERM)")
+	}
+	if err := display.SetTheme(args[0]); err != nil {
+		return err
+	}
+
+	display.Println(display.Cyan, "Theme set to: %s", display.CurrentTheme())
+	return nil
+}
+
+func styleHandler(s *session.Session, args []string) error {
+	if len(args) == 0 {
+		for _, name := range display.BoardStyles() {
+			marker := "  "
+			if name == display.BoardStyle() {
+				marker = "* "
+			}
+			fmt.Printf("%s%s\n", marker, name)
+		}
+		return nil
+	}
+
+	if err := display.SetBoardStyle(args[0]); err != nil {
+		return err
+	}
+
+	display.Println(display.Cyan, "Board style set to: %s", display.BoardStyle())
+	return nil
+}
+
+func flipHandler(s *session.Session, args []string) error {
+	display.SetFlipped(!display.Flipped())
+
+	perspective := "white"
+	if display.Flipped() {
+		perspective = "black"
+	}
+	display.Println(display.Cyan, "Board shown from %s's side", perspective)
+	return nil
+}
+
 func rawRequestHandler(s *session.Session, args []string) error {
 	if len(args) < 2 {
 		return fmt.Errorf("usage: raw <method> <path> [json-body]")
@@ -11,8 +11,14 @@ import (
 	"chess/internal/client/api"
 	"chess/internal/client/display"
 	"chess/internal/client/session"
+	"chess/internal/server/board"
+	"chess/internal/server/core"
+	"chess/internal/server/pgn"
 )
 
+// computerWaitSlack covers engine queueing on top of the computer's search time
+const computerWaitSlack = 5 * time.Second
+
 func (r *Registry) registerGameCommands() {
 	r.Register(&Command{
 		Name:        "new",
@@ -170,7 +176,14 @@ func newGameHandler(s *session.Session, args []string) error {
 		FEN:   fen,
 	}
 
-	resp, err := c.CreateGame(req)
+	// A computer playing white moves before the game is returned
+	var resp *api.GameResponse
+	var err error
+	if white.Type == 2 {
+		resp, err = c.CreateGameWaitFirstMoveContext(s.Context(), req)
+	} else {
+		resp, err = c.CreateGame(req)
+	}
 	if err != nil {
 		return err
 	}
@@ -191,9 +204,11 @@ func newGameHandler(s *session.Session, args []string) error {
 	display.Println(display.Green, "Game created: %s", resp.GameID)
 	display.Println(display.Cyan, "Current game set to: %s", resp.GameID)
 
-	// If white is computer, inform user to trigger move
-	if white.Type == 2 {
-		display.Println(display.Magenta, "\nWhite is computer. Use 'computer' or 'c' to trigger first move.")
+	switch {
+	case resp.State == "pending":
+		display.Println(display.Magenta, "\nComputer is still thinking. Use 'poll' or 'p' to wait for its move.")
+	case white.Type == 2 && resp.LastMove != nil && len(resp.Moves) > 0:
+		display.Println(display.Magenta, "Computer played: %s", lastMoveSAN(resp))
 	}
 
 	return nil
@@ -228,7 +243,7 @@ func joinGameHandler(s *session.Session, args []string) error {
 		}
 	}
 
-	fmt.Printf("%sJoined game: %s%s\n", display.Green, gameID, display.Reset)
+	display.Println(display.Green, "Joined game: %s", gameID)
 	fmt.Printf("Turn: %s | State: %s | Moves: %d\n", resp.Turn, resp.State, len(resp.Moves))
 
 	return nil
@@ -257,18 +272,11 @@ func moveHandler(s *session.Session, args []string) error {
 	display.Println(display.Green, "Move accepted")
 
 	// Check if game ended
-	switch resp.State {
-	case "checkmate":
-		winner := "Black"
-		if resp.Turn == "b" { // Turn switches after move, so if black's turn after checkmate, white won
-			winner = "White"
-		}
-		display.Println(display.Green, "\nCHECKMATE! %s wins!", winner)
-	case "stalemate":
-		display.Println(display.Yellow, "\nSTALEMATE! Game drawn.")
-	case "draw":
-		display.Println(display.Yellow, "\nDRAW! Game drawn.")
-	case "ongoing":
+	if announceGameEnd(s, resp) {
+		return nil
+	}
+
+	if resp.State == "ongoing" {
 		// Check if computer needs to play
 		currentTurn := resp.Turn
 		var computerPlayer *api.PlayerInfo
@@ -302,39 +310,35 @@ func computerMoveHandler(s *session.Session, args []string) error {
 	if resp.State == "pending" {
 		display.Println(display.Magenta, "Computer is thinking...")
 
-		// Poll for completion
-		for i := 0; i < 50; i++ {
-			time.Sleep(200 * time.Millisecond)
-			resp2, err := c.GetGame(gameID)
-			if err == nil && resp2.State != "pending" {
-				s.LastMoveCount = len(resp2.Moves)
-				s.CurrentGameState = resp2
-				if resp2.LastMove != nil {
-					display.Print(display.Magenta, "Computer played: %s", resp2.LastMove.Move)
-					if resp2.LastMove.Depth > 0 {
-						fmt.Printf(" (depth %d, score %d)", resp2.LastMove.Depth, resp2.LastMove.Score)
-					}
-					fmt.Println()
-				}
-
-				// Check if game ended after computer move
-				switch resp2.State {
-				case "checkmate":
-					winner := "Black"
-					if resp2.Turn == "b" {
-						winner = "White"
-					}
-					display.Println(display.Green, "\nCHECKMATE! %s wins!", winner)
-				case "stalemate":
-					display.Println(display.Yellow, "\nSTALEMATE! Game drawn.")
-				case "draw":
-					display.Println(display.Yellow, "\nDRAW! Game drawn.")
-				}
-
-				return nil
+		// Turn has not passed yet, so it still names the computer
+		player := resp.Players.White
+		if resp.Turn == "b" {
+			player = resp.Players.Black
+		}
+
+		searchTime := player.SearchTime
+		if player.EffectiveSearchTime > 0 {
+			searchTime = player.EffectiveSearchTime
+		}
+
+		resp2, err := waitForComputerMove(s, gameID, len(resp.Moves), searchTime)
+		if err != nil {
+			return err
+		}
+
+		s.LastMoveCount = len(resp2.Moves)
+		s.CurrentGameState = resp2
+		if resp2.LastMove != nil {
+			display.Print(display.Magenta, "Computer played: %s", lastMoveSAN(resp2))
+			if resp2.LastMove.Depth > 0 {
+				fmt.Printf(" (depth %d, score %d)", resp2.LastMove.Depth, resp2.LastMove.Score)
 			}
+			fmt.Println()
 		}
-		return fmt.Errorf("timeout waiting for computer move")
+
+		// Check if game ended after computer move
+		announceGameEnd(s, resp2)
+		return nil
 	}
 
 	s.LastMoveCount = len(resp.Moves)
@@ -343,6 +347,67 @@ func computerMoveHandler(s *session.Session, args []string) error {
 	return nil
 }
 
+// waitForComputerMove waits until a pending computer move completes
+// The long-poll endpoint is preferred, interval polling is the fallback if it fails
+func waitForComputerMove(s *session.Session, gameID string, moveCount, searchTime int) (*api.GameResponse, error) {
+	c := s.Client
+	ctx := s.Context()
+
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = session.DefaultPollInterval
+	}
+	attempts := s.PollAttempts
+	if attempts <= 0 {
+		attempts = session.DefaultPollAttempts
+	}
+
+	// The server may search for twice the configured time before giving up,
+	// so wait at least that long plus slack for queueing
+	wait := max(
+		time.Duration(attempts)*interval,
+		time.Duration(searchTime*2)*time.Millisecond+computerWaitSlack,
+	)
+	deadline := time.Now().Add(wait)
+
+	longPoll := true
+	for time.Now().Before(deadline) {
+		var (
+			resp *api.GameResponse
+			err  error
+		)
+		if longPoll {
+			resp, err = c.GetGameWithPollContext(ctx, gameID, moveCount)
+		} else {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			resp, err = c.GetGameContext(ctx, gameID)
+		}
+		if err != nil {
+			// A deleted game never finishes its move and an interrupt ends
+			// the wait, other failures are retried
+			if api.ErrorCode(err) == api.ErrGameNotFound || ctx.Err() != nil {
+				return nil, err
+			}
+			longPoll = false
+			continue
+		}
+
+		if resp.State != "pending" {
+			return resp, nil
+		}
+		// The move can land just before the state update, which does not wake waiters
+		if len(resp.Moves) != moveCount {
+			longPoll = false
+		}
+	}
+
+	return nil, fmt.Errorf("timeout waiting for computer move after %s", wait.Round(time.Second))
+}
+
 func undoHandler(s *session.Session, args []string) error {
 	gameID := s.GetCurrentGame()
 	if gameID == "" {
@@ -364,6 +429,10 @@ func undoHandler(s *session.Session, args []string) error {
 		return err
 	}
 
+	// The server may undo the computer's reply as well
+	if undone := s.GetLastMoveCount() - len(resp.Moves); undone > count {
+		count = undone
+	}
 	s.SetLastMoveCount(len(resp.Moves))
 	s.SetGameState(resp)
 	display.Println(display.Green, "Undid %d move(s)", count)
@@ -396,6 +465,9 @@ func showBoardHandler(s *session.Session, args []string) error {
 	// Display board with colors
 	fmt.Println()
 	display.RenderBoard(board.Board)
+	if board.InCheck && !board.GameOver {
+		display.Println(display.Yellow, "Check")
+	}
 
 	// Display game info
 	fmt.Printf("\nFEN: %s\n", game.FEN)
@@ -404,18 +476,7 @@ func showBoardHandler(s *session.Session, args []string) error {
 
 	// Display move history
 	if len(game.Moves) > 0 {
-		fmt.Printf("\nHistory: ")
-		for i, move := range game.Moves {
-			if i > 0 {
-				fmt.Print(" ")
-			}
-			if i%2 == 0 {
-				fmt.Printf("%d.%s", (i/2)+1, move)
-			} else {
-				fmt.Printf(" %s", move)
-			}
-		}
-		fmt.Println()
+		printHistory(game.InitialFEN, game.Moves, s.IsVerbose())
 	}
 
 	// Display last move info
@@ -424,7 +485,7 @@ func showBoardHandler(s *session.Session, args []string) error {
 		if game.LastMove.PlayerColor == "b" {
 			color = "Black"
 		}
-		fmt.Printf("Last move: %s by %s", game.LastMove.Move, color)
+		fmt.Printf("Last move: %s by %s", lastMoveSAN(game), color)
 		if game.LastMove.Depth > 0 {
 			fmt.Printf(" (depth %d, score %d)", game.LastMove.Depth, game.LastMove.Score)
 		}
@@ -434,6 +495,74 @@ func showBoardHandler(s *session.Session, args []string) error {
 	return nil
 }
 
+// lastMoveSAN formats the last move as SAN with its UCI, or as UCI alone if
+// the moves cannot be replayed
+func lastMoveSAN(g *api.GameResponse) string {
+	n := len(g.Moves)
+	if n == 0 || g.Moves[n-1] != g.LastMove.Move {
+		return g.LastMove.Move
+	}
+	san, err := toSAN(g.InitialFEN, g.Moves[:n-1], g.LastMove.Move)
+	if err != nil {
+		return g.LastMove.Move
+	}
+	return fmt.Sprintf("%s (%s)", san, g.LastMove.Move)
+}
+
+// printHistory prints the move list in SAN, falling back to UCI if the moves cannot be replayed
+// Verbose mode lists each move with its UCI and resulting FEN
+func printHistory(initialFEN string, moves []string, verbose bool) {
+	plies, err := pgn.Replay(initialFEN, moves)
+	if err != nil {
+		display.Println(display.Yellow, "\nCannot render SAN (%s), showing UCI", err.Error())
+		plies = make([]pgn.Ply, len(moves))
+		for i, move := range moves {
+			plies[i] = pgn.Ply{UCI: move, SAN: move}
+		}
+	}
+
+	// Move numbering follows the starting position
+	moveNumber, blackToMove := 1, false
+	if b, err := board.ParseFEN(initialFEN); err == nil {
+		moveNumber, blackToMove = b.FullMove(), b.Turn() == core.ColorBlack
+	}
+
+	if verbose {
+		fmt.Println("\nHistory:")
+		for _, ply := range plies {
+			dots := "."
+			if blackToMove {
+				dots = "..."
+			}
+			fmt.Printf("  %d%s %-8s %-6s %s\n", moveNumber, dots, ply.SAN, ply.UCI, ply.FEN)
+			if blackToMove {
+				moveNumber++
+			}
+			blackToMove = !blackToMove
+		}
+		return
+	}
+
+	fmt.Printf("\nHistory: ")
+	for i, ply := range plies {
+		if i > 0 {
+			fmt.Print(" ")
+		}
+		switch {
+		case !blackToMove:
+			fmt.Printf("%d. ", moveNumber)
+		case i == 0:
+			fmt.Printf("%d... ", moveNumber)
+		}
+		fmt.Print(ply.SAN)
+		if blackToMove {
+			moveNumber++
+		}
+		blackToMove = !blackToMove
+	}
+	fmt.Println()
+}
+
 func gameStateHandler(s *session.Session, args []string) error {
 	gameID := s.GetCurrentGame()
 	if gameID == "" {
@@ -476,7 +605,7 @@ func deleteGameHandler(s *session.Session, args []string) error {
 		s.SetLastMoveCount(0)
 	}
 
-	fmt.Printf("%sGame deleted: %s%s\n", display.Green, gameID, display.Reset)
+	display.Println(display.Green, "Game deleted: %s", gameID)
 	return nil
 }
 
@@ -492,7 +621,7 @@ func pollHandler(s *session.Session, args []string) error {
 	display.Println(display.Cyan, "Long-polling for updates (move count: %d)...", moveCount)
 	display.Println(display.Cyan, "This may take up to 25 seconds")
 
-	resp, err := c.GetGameWithPoll(gameID, moveCount)
+	resp, err := c.GetGameWithPollContext(s.Context(), gameID, moveCount)
 	if err != nil {
 		return err
 	}
@@ -503,7 +632,7 @@ func pollHandler(s *session.Session, args []string) error {
 	if len(resp.Moves) > moveCount {
 		display.Println(display.Green, "Game updated! New moves detected")
 		if resp.LastMove != nil {
-			fmt.Printf("Last move: %s\n", resp.LastMove.Move)
+			fmt.Printf("Last move: %s\n", lastMoveSAN(resp))
 		}
 	} else {
 		display.Println(display.Yellow, "No updates (timeout)")
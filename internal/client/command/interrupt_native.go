@@ -0,0 +1,15 @@
+//go:build !js && !wasm
+
+package command
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// commandContext is cancelled by Ctrl-C while a command runs, outside
+// commands Ctrl-C keeps its default behavior of exiting the client
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt)
+}
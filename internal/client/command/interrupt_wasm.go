@@ -0,0 +1,10 @@
+//go:build js && wasm
+
+package command
+
+import "context"
+
+// commandContext has no interrupt in the browser, commands run to completion
+func commandContext() (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.Background())
+}
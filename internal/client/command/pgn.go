@@ -0,0 +1,201 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"chess/internal/client/api"
+	"chess/internal/client/display"
+	"chess/internal/client/session"
+	"chess/internal/server/pgn"
+)
+
+func (r *Registry) registerPGNCommands() {
+	r.Register(&Command{
+		Name:        "save",
+		ShortName:   "w",
+		Description: "Save current game to PGN file",
+		Usage:       "save [file.pgn]",
+		Handler:     saveHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "load",
+		ShortName:   "f",
+		Description: "Create a game from a PGN file and replay its moves",
+		Usage:       "load <file.pgn>",
+		Handler:     loadHandler,
+	})
+}
+
+func saveHandler(s *session.Session, args []string) error {
+	gameID := s.GetCurrentGame()
+	if gameID == "" {
+		return fmt.Errorf("no current game, use 'new' or 'join <gameId>'")
+	}
+
+	path := fmt.Sprintf("chess-%s.pgn", gameID[:8])
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	saved, err := SaveGame(s, path)
+	if err != nil {
+		return err
+	}
+	if saved {
+		display.Println(display.Green, "Game saved to: %s", path)
+	}
+	return nil
+}
+
+// AutoSave writes the active game to the session's auto-save path on exit
+func AutoSave(s *session.Session) {
+	if s.AutoSavePath == "" || s.GetCurrentGame() == "" {
+		return
+	}
+
+	saved, err := SaveGame(s, s.AutoSavePath)
+	if err != nil {
+		display.Println(display.Red, "Auto-save failed: %s", err.Error())
+		return
+	}
+	if saved {
+		display.Println(display.Green, "Game auto-saved to: %s", s.AutoSavePath)
+	}
+}
+
+// SaveGame fetches the current game and writes it as PGN
+// Returns false without error if the user declines to overwrite an existing file
+func SaveGame(s *session.Session, path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil && !confirm(fmt.Sprintf("File %s exists, overwrite? (y/n) [n]: ", path)) {
+		display.Println(display.Yellow, "Save cancelled")
+		return false, nil
+	}
+
+	c := s.GetClient().(*api.Client)
+	game, err := c.GetGame(s.GetCurrentGame())
+	if err != nil {
+		return false, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+
+	record := &pgn.Game{
+		Event:      "Chess Game",
+		Site:       s.GetAPIBaseURL(),
+		Date:       time.Now(),
+		Round:      "-",
+		White:      playerLabel(game.Players.White),
+		Black:      playerLabel(game.Players.Black),
+		Result:     resultForState(game.State),
+		InitialFEN: game.InitialFEN,
+		Moves:      game.Moves,
+		Extra:      []pgn.Tag{{Name: "GameId", Value: game.GameID}},
+	}
+	if err := pgn.Write(f, record); err != nil {
+		return false, fmt.Errorf("failed to write PGN: %w", err)
+	}
+
+	return true, f.Close()
+}
+
+func loadHandler(s *session.Session, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: load <file.pgn>")
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// A replay error still yields the moves before the illegal one
+	games, parseErr := pgn.Parse(string(data))
+	if len(games) == 0 {
+		return parseErr
+	}
+	g := games[0]
+
+	// Games before the failing one are complete, the failing one is only
+	// returned when its moves up to the error could be replayed
+	failed := len(games) + 1
+	var replayErr *pgn.ReplayError
+	if errors.As(parseErr, &replayErr) {
+		failed = len(games)
+	}
+	switch {
+	case parseErr != nil && failed == 1:
+		display.Println(display.Yellow, "Replay stopped at %s", parseErr.Error())
+	case parseErr != nil:
+		display.Println(display.Yellow, "Replay stopped in game %d at %s, loading the first", failed, parseErr.Error())
+	case len(games) > 1:
+		display.Println(display.Yellow, "File contains %d games, loading the first", len(games))
+	}
+
+	c := s.GetClient().(*api.Client)
+	resp, err := c.CreateGame(&api.CreateGameRequest{
+		White: api.PlayerConfig{Type: 1},
+		Black: api.PlayerConfig{Type: 1},
+		FEN:   g.InitialFEN,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.CurrentGame = resp.GameID
+	s.CurrentGameState = resp
+	display.Println(display.Green, "Game created: %s", resp.GameID)
+
+	for i, move := range g.Moves {
+		moveResp, err := c.MakeMove(resp.GameID, move)
+		if err != nil {
+			display.Println(display.Red, "Server rejected ply %d (%s): %s", i+1, move, err.Error())
+			break
+		}
+		s.CurrentGameState = moveResp
+	}
+
+	s.SetLastMoveCount(len(s.CurrentGameState.Moves))
+	display.Println(display.Cyan, "Replayed %d of %d moves, current game set to: %s",
+		len(s.CurrentGameState.Moves), len(g.Moves), resp.GameID)
+	return nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(prompt string) bool {
+	scanner := bufio.NewScanner(os.Stdin)
+	display.Print(display.Yellow, prompt)
+	scanner.Scan()
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
+
+func playerLabel(p api.PlayerInfo) string {
+	if p.Type == 2 {
+		return fmt.Sprintf("Stockfish (level %d)", p.Level)
+	}
+	return p.ID
+}
+
+// resultForState maps the API game state to PGN result notation
+func resultForState(state string) string {
+	switch state {
+	case "white wins":
+		return "1-0"
+	case "black wins":
+		return "0-1"
+	case "draw", "stalemate":
+		return "1/2-1/2"
+	default:
+		return "*"
+	}
+}
@@ -1,6 +1,8 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -34,6 +36,9 @@ func NewRegistry(s *session.Session) *Registry {
 	r.registerGameCommands()
 	r.registerAuthCommands()
 	r.registerDebugCommands()
+	r.registerPGNCommands()
+	r.registerReviewCommands()
+	r.registerAnalysisCommands()
 
 	// Help command
 	r.Register(&Command{
@@ -84,8 +89,49 @@ func (r *Registry) Execute(input string) {
 		cl.SetVerbose(r.session.IsVerbose())
 	}
 
+	ctx, stop := commandContext()
+	defer stop()
+	r.session.SetContext(ctx)
+	defer r.session.SetContext(nil)
+
 	if err := cmd.Handler(r.session, args); err != nil {
+		reportError(err)
+	}
+}
+
+// reportError prints a command error, server errors were already shown by
+// the client, so they only get a hint on what to do next
+func reportError(err error) {
+	if errors.Is(err, context.Canceled) {
+		display.Println(display.Yellow, "Interrupted")
+		return
+	}
+
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
 		display.Println(display.Red, "Error: %s", err.Error())
+		return
+	}
+
+	switch apiErr.Code {
+	case api.ErrGameNotFound:
+		display.Println(display.Yellow, "No such game, use 'new' or 'join <gameId>'")
+	case api.ErrRateLimitExceeded:
+		display.Println(display.Yellow, "Rate limited, wait a moment before retrying")
+	case api.ErrUnauthorized:
+		display.Println(display.Yellow, "Not authorized, use 'login' or check the game's players")
+	case api.ErrMoveConflict:
+		display.Println(display.Yellow, "Game changed since it was last fetched, use 'show' to refresh")
+	case api.ErrNotHumanTurn:
+		display.Println(display.Yellow, "Not your turn, use 'computer' to trigger a computer move")
+	case api.ErrGameOver:
+		display.Println(display.Yellow, "Game is over, use 'undo' or start a 'new' game")
+	case api.ErrResourceLimit:
+		display.Println(display.Yellow, "Server is at capacity, try again later")
+	case api.ErrEngineSaturated:
+		display.Println(display.Yellow, "Server engines are busy, try again later")
+	case "":
+		display.Println(display.Red, "Error: %s", apiErr.Error())
 	}
 }
 
@@ -126,6 +172,14 @@ func (r *Registry) helpHandler(s *session.Session, args []string) error {
 		{"state", "s", ""},
 		{"delete", "d", ""},
 		{"poll", "p", ""},
+		{"prev", "<", ""},
+		{"next", ">", ""},
+		{"goto", "g", ""},
+		{"live", "v", ""},
+		{"save", "w", ""},
+		{"load", "f", ""},
+		{"analyze", "a", ""},
+		{"hint", "k", ""},
 	}
 
 	authCommands := []cmdInfo{
@@ -139,6 +193,9 @@ func (r *Registry) helpHandler(s *session.Session, args []string) error {
 	utilCommands := []cmdInfo{
 		{"health", ".", ""},
 		{"url", "/", ""},
+		{"theme", "t", ""},
+		{"style", "y", ""},
+		{"flip", "b", ""},
 		{"raw", ":", ""},
 		{"help", "?", ""},
 		{"exit", "x", ""},
@@ -150,7 +207,7 @@ func (r *Registry) helpHandler(s *session.Session, args []string) error {
 			if cmd, exists := r.commands[info.name]; exists {
 				shortPart := ""
 				if info.shortName != "" {
-					shortPart = fmt.Sprintf("[%s%s%s] ", display.Cyan, info.shortName, display.Reset)
+					shortPart = fmt.Sprintf("[%s] ", display.C(display.Cyan, info.shortName))
 				}
 				fmt.Printf("  %s%-10s %s\n", shortPart, cmd.Name, cmd.Description)
 			}
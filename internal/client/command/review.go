@@ -0,0 +1,146 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+
+	"chess/internal/client/api"
+	"chess/internal/client/display"
+	"chess/internal/client/session"
+	"chess/internal/server/board"
+	"chess/internal/server/pgn"
+)
+
+func (r *Registry) registerReviewCommands() {
+	r.Register(&Command{
+		Name:        "prev",
+		ShortName:   "<",
+		Description: "Step back one ply in review mode",
+		Usage:       "prev",
+		Handler:     prevHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "next",
+		ShortName:   ">",
+		Description: "Step forward one ply in review mode",
+		Usage:       "next",
+		Handler:     nextHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "goto",
+		ShortName:   "g",
+		Description: "View the position after a ply (0 = start)",
+		Usage:       "goto <ply>",
+		Handler:     gotoHandler,
+	})
+
+	r.Register(&Command{
+		Name:        "live",
+		ShortName:   "v",
+		Description: "Leave review mode and show the current position",
+		Usage:       "live",
+		Handler:     liveHandler,
+	})
+}
+
+func prevHandler(s *session.Session, args []string) error {
+	return stepView(s, -1)
+}
+
+func nextHandler(s *session.Session, args []string) error {
+	if !s.IsReviewing() {
+		return fmt.Errorf("not in review mode, use 'prev' or 'goto <ply>'")
+	}
+	return stepView(s, 1)
+}
+
+func gotoHandler(s *session.Session, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: goto <ply>")
+	}
+	ply, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid ply: %s", args[0])
+	}
+
+	plies, initialFEN, err := loadReview(s)
+	if err != nil {
+		return err
+	}
+	if ply < 0 || ply > len(plies) {
+		return fmt.Errorf("ply must be between 0 and %d", len(plies))
+	}
+
+	s.ViewGame = s.GetCurrentGame()
+	s.ViewPly = ply
+	return renderView(s, plies, initialFEN)
+}
+
+func liveHandler(s *session.Session, args []string) error {
+	s.ViewGame = ""
+	return showBoardHandler(s, nil)
+}
+
+// stepView moves the view pointer, entering review mode at the live position
+func stepView(s *session.Session, delta int) error {
+	plies, initialFEN, err := loadReview(s)
+	if err != nil {
+		return err
+	}
+
+	ply := len(plies)
+	if s.IsReviewing() {
+		ply = s.ViewPly
+	}
+	ply += delta
+	if ply < 0 || ply > len(plies) {
+		return fmt.Errorf("no more moves in that direction")
+	}
+
+	s.ViewGame = s.GetCurrentGame()
+	s.ViewPly = ply
+	return renderView(s, plies, initialFEN)
+}
+
+// loadReview fetches the game and replays its moves locally without modifying it
+func loadReview(s *session.Session) ([]pgn.Ply, string, error) {
+	gameID := s.GetCurrentGame()
+	if gameID == "" {
+		return nil, "", fmt.Errorf("no current game, use 'new' or 'join <gameId>'")
+	}
+
+	c := s.GetClient().(*api.Client)
+	game, err := c.GetGame(gameID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	plies, err := pgn.Replay(game.InitialFEN, game.Moves)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot replay game: %w", err)
+	}
+	return plies, game.InitialFEN, nil
+}
+
+func renderView(s *session.Session, plies []pgn.Ply, initialFEN string) error {
+	fen := initialFEN
+	if s.ViewPly > 0 {
+		fen = plies[s.ViewPly-1].FEN
+	}
+
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	display.RenderBoard(b.ToASCII(board.ASCIIOptions{}))
+	fmt.Printf("\nFEN: %s\n", fen)
+	if s.ViewPly > 0 {
+		fmt.Printf("Move: %s\n", plies[s.ViewPly-1].SAN)
+	}
+	display.Println(display.Yellow, "(viewing ply %d of %d)", s.ViewPly, len(plies))
+	return nil
+}
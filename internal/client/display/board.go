@@ -5,49 +5,186 @@ import (
 	"strings"
 )
 
-// RenderBoard renders an ASCII board with colored pieces
+// Board styles control square width and borders
+const (
+	StyleASCII   = "ascii"   // Two columns per square, coordinates on all sides
+	StyleBox     = "box"     // Three columns per square inside a Unicode border
+	StyleCompact = "compact" // One column per square for narrow terminals
+)
+
+var (
+	boardStyle = StyleASCII
+	flipped    bool
+)
+
+// SetBoardStyle selects the board rendering style
+func SetBoardStyle(name string) error {
+	name = strings.ToLower(name)
+	switch name {
+	case StyleASCII, StyleBox, StyleCompact:
+		boardStyle = name
+		return nil
+	default:
+		return fmt.Errorf("unknown board style: %s", name)
+	}
+}
+
+// BoardStyle returns the active board style
+func BoardStyle() string {
+	return boardStyle
+}
+
+// BoardStyles returns the available board style names
+func BoardStyles() []string {
+	return []string{StyleASCII, StyleBox, StyleCompact}
+}
+
+// SetFlipped renders the board from black's perspective when true
+func SetFlipped(f bool) {
+	flipped = f
+}
+
+// Flipped reports whether the board is rendered from black's perspective
+func Flipped() bool {
+	return flipped
+}
+
+// RenderBoard renders a server ASCII board in the active style and theme
 func RenderBoard(asciiBoard string) {
+	grid, ok := parseASCII(asciiBoard)
+	if !ok {
+		// Unknown layout, print as received
+		fmt.Println(asciiBoard)
+		return
+	}
+
+	// Files and ranks in display order, indexes into grid
+	files := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	ranks := []int{0, 1, 2, 3, 4, 5, 6, 7} // grid row 0 is rank 8
+	if flipped {
+		for i := 0; i < 4; i++ {
+			files[i], files[7-i] = files[7-i], files[i]
+			ranks[i], ranks[7-i] = ranks[7-i], ranks[i]
+		}
+	}
+
+	theme := themes[themeName]
+	coords := themeColor(theme.Coords)
+
+	var header strings.Builder
+	switch boardStyle {
+	case StyleBox:
+		header.WriteString("   ")
+		for _, f := range files {
+			header.WriteString(" " + string(rune('a'+f)) + " ")
+		}
+	case StyleCompact:
+		header.WriteString("  ")
+		for _, f := range files {
+			header.WriteString(string(rune('a' + f)))
+		}
+	default:
+		header.WriteString("  ")
+		for _, f := range files {
+			header.WriteString(string(rune('a'+f)) + " ")
+		}
+	}
+	fileLabels := C(coords, strings.TrimRight(header.String(), " "))
+
+	border := func(left, right string) {
+		fmt.Println("  " + C(coords, left+strings.Repeat("─", 24)+right))
+	}
+
+	fmt.Println(fileLabels)
+	if boardStyle == StyleBox {
+		border("┌", "┐")
+	}
+	for _, r := range ranks {
+		rank := C(coords, fmt.Sprintf("%d", 8-r))
+
+		// Grid row 0 is rank 8, so a square is light when file and row share parity
+		var row strings.Builder
+		for _, f := range files {
+			row.WriteString(renderSquare(theme, grid[r][f], (f+r)%2 == 0))
+		}
+
+		switch boardStyle {
+		case StyleBox:
+			edge := C(coords, "│")
+			fmt.Printf("%s %s%s%s %s\n", rank, edge, row.String(), edge, rank)
+		case StyleCompact:
+			fmt.Printf("%s %s %s\n", rank, row.String(), rank)
+		default:
+			fmt.Printf("%s %s%s\n", rank, row.String(), rank)
+		}
+	}
+	if boardStyle == StyleBox {
+		border("└", "┘")
+	}
+	fmt.Println(fileLabels)
+}
+
+// renderSquare returns one padded, colored square for the active style
+func renderSquare(theme Theme, piece byte, light bool) string {
+	bg := themeBackground(theme.Dark)
+	if light {
+		bg = themeBackground(theme.Light)
+	}
+
+	var fg string
+	switch {
+	case piece >= 'A' && piece <= 'Z':
+		fg = themeColor(theme.White)
+	case piece >= 'a' && piece <= 'z':
+		fg = themeColor(theme.Black)
+	default:
+		fg = themeColor(theme.Empty)
+		// Shaded squares already mark emptiness
+		if bg != "" {
+			piece = ' '
+		}
+	}
+
+	// Without a background only the piece is colored, padding stays plain
+	// so the reset code does not shift alignment
+	left, right := "", ""
+	switch boardStyle {
+	case StyleBox:
+		left, right = " ", " "
+	case StyleASCII:
+		right = " "
+	}
+	if bg == "" {
+		return left + C(fg, string(piece)) + right
+	}
+	return C(bg+fg, left+string(piece)+right)
+}
+
+// parseASCII reads the 8x8 piece grid from the server board layout
+// Rank lines are "8 r n b q k b n r 8" with squares at every second column
+func parseASCII(asciiBoard string) ([8][8]byte, bool) {
+	var grid [8][8]byte
 	lines := strings.Split(asciiBoard, "\n")
+	if len(lines) < 9 {
+		return grid, false
+	}
 
-	for i, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-
-		isRankLine := (i == 0) || (i == 9)
-
-		// Process each character
-		for _, char := range line {
-			switch {
-			case char >= 'a' && char <= 'h' && isRankLine:
-				// File letters - Cyan
-				Print(Cyan, "%c", char)
-			case char >= 'A' && char <= 'Z':
-				// White pieces - Blue
-				Print(Blue, "%c", char)
-			case char >= 'a' && char <= 'z' && !isRankLine:
-				// Black pieces - Red
-				Print(Red, "%c", char)
-			case char == '.':
-				// Empty squares
-				Print(White, ".")
-			case char >= '1' && char <= '8':
-				// Rank numbers - Cyan
-				Print(Cyan, "%c", char)
-			case char == ' ':
-				Print(Reset, " ")
-			default:
-				Print(Reset, "%c", char)
-			}
-		}
-		fmt.Println()
+	for r := 0; r < 8; r++ {
+		line := lines[r+1]
+		if len(line) < 17 {
+			return grid, false
+		}
+		for f := 0; f < 8; f++ {
+			grid[r][f] = line[2+2*f]
+		}
 	}
+	return grid, true
 }
 
 // ColorForTurn returns colored turn indicator
 func ColorForTurn(turn string) string {
 	if turn == "w" {
-		return Blue + "White" + Reset
+		return C(Blue, "White")
 	}
-	return Red + "Black" + Reset
+	return C(Red, "Black")
 }
\ No newline at end of file
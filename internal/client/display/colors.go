@@ -17,8 +17,11 @@ const (
 	White   = "\033[37m"
 )
 
-// C wraps text with color and reset codes
+// C wraps text with color and reset codes, plain text when colors are off
 func C(color, text string) string {
+	if colorMode == ModeOff || color == "" {
+		return text
+	}
 	return color + text + Reset
 }
 
@@ -48,5 +51,5 @@ func (b *Builder) String() string {
 
 // Prompt returns a colored prompt string
 func Prompt(text string) string {
-	return Yellow + text + Yellow + " > " + Reset
+	return C(Yellow, text+" > ")
 }
\ No newline at end of file
@@ -0,0 +1,43 @@
+//go:build !js && !wasm
+
+package display
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// detectColorMode inspects NO_COLOR, COLORTERM and TERM
+func detectColorMode() ColorMode {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return ModeOff
+	}
+
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return ModeTrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "" || term == "dumb":
+		return ModeOff
+	case strings.Contains(term, "256color"):
+		return Mode256
+	default:
+		return Mode16
+	}
+}
+
+// themeConfigPath returns CHESS_THEMES or the default user config location
+func themeConfigPath() string {
+	if path := os.Getenv("CHESS_THEMES"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "chess-client", "themes.json")
+}
@@ -0,0 +1,13 @@
+//go:build js && wasm
+
+package display
+
+// detectColorMode assumes the browser terminal (xterm.js) supports truecolor
+func detectColorMode() ColorMode {
+	return ModeTrueColor
+}
+
+// themeConfigPath returns no path, theme config files are not available in the browser
+func themeConfigPath() string {
+	return ""
+}
@@ -0,0 +1,293 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColorMode is the color capability of the terminal
+type ColorMode int
+
+const (
+	ModeOff ColorMode = iota
+	Mode16
+	Mode256
+	ModeTrueColor
+)
+
+func (m ColorMode) String() string {
+	switch m {
+	case Mode16:
+		return "16"
+	case Mode256:
+		return "256"
+	case ModeTrueColor:
+		return "truecolor"
+	default:
+		return "off"
+	}
+}
+
+// Theme maps board roles to color specs
+// A spec is a 16-color name ("red", "bright-blue"), a 256-color index ("208")
+// or a truecolor hex value ("#ff8700"); specs are downgraded to the terminal mode
+type Theme struct {
+	White  string `json:"white"`           // White pieces
+	Black  string `json:"black"`           // Black pieces
+	Empty  string `json:"empty"`           // Empty squares
+	Coords string `json:"coords"`          // File letters and rank numbers
+	Light  string `json:"light,omitempty"` // Light square background, optional
+	Dark   string `json:"dark,omitempty"`  // Dark square background, optional
+}
+
+// ThemeConfig is the on-disk theme configuration
+type ThemeConfig struct {
+	Theme  string           `json:"theme,omitempty"` // Theme selected at startup
+	Style  string           `json:"style,omitempty"` // Board style selected at startup
+	Themes map[string]Theme `json:"themes"`
+}
+
+var (
+	colorMode = ModeOff
+	themes    = map[string]Theme{
+		"default":  {White: "blue", Black: "red", Empty: "white", Coords: "cyan"},
+		"contrast": {White: "bright-white", Black: "bright-red", Empty: "bright-black", Coords: "bright-yellow"},
+		"warm":     {White: "#ffd787", Black: "#d75f00", Empty: "#8a8a8a", Coords: "#d7af5f"},
+		"wood":     {White: "#ffffff", Black: "#000000", Empty: "#8a8a8a", Coords: "#d7af5f", Light: "#d7af87", Dark: "#875f00"},
+		"off":      {},
+	}
+	themeName = "default"
+)
+
+// basic16 holds the ANSI 16-color names with approximate RGB values
+var basic16 = []struct {
+	name    string
+	code    int
+	r, g, b int
+}{
+	{"black", 30, 0, 0, 0},
+	{"red", 31, 205, 0, 0},
+	{"green", 32, 0, 205, 0},
+	{"yellow", 33, 205, 205, 0},
+	{"blue", 34, 0, 0, 238},
+	{"magenta", 35, 205, 0, 205},
+	{"cyan", 36, 0, 205, 205},
+	{"white", 37, 229, 229, 229},
+	{"bright-black", 90, 127, 127, 127},
+	{"bright-red", 91, 255, 0, 0},
+	{"bright-green", 92, 0, 255, 0},
+	{"bright-yellow", 93, 255, 255, 0},
+	{"bright-blue", 94, 92, 92, 255},
+	{"bright-magenta", 95, 255, 0, 255},
+	{"bright-cyan", 96, 0, 255, 255},
+	{"bright-white", 97, 255, 255, 255},
+}
+
+// InitColors detects terminal color support and loads custom themes
+// Config load failures are reported but leave the built-in themes usable
+func InitColors() {
+	colorMode = detectColorMode()
+	if colorMode == ModeOff {
+		themeName = "off"
+	}
+
+	path := themeConfigPath()
+	if path == "" {
+		return
+	}
+	if err := LoadThemes(path); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Warning: failed to load themes from %s: %v\n", path, err)
+	}
+}
+
+// LoadThemes registers themes from a JSON config file and applies its startup theme
+func LoadThemes(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var cfg ThemeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("invalid theme config: %w", err)
+	}
+
+	for name, t := range cfg.Themes {
+		for _, spec := range []string{t.White, t.Black, t.Empty, t.Coords, t.Light, t.Dark} {
+			if _, ok := parseSpec(spec); !ok {
+				return fmt.Errorf("theme %s: invalid color %q", name, spec)
+			}
+		}
+		themes[strings.ToLower(name)] = t
+	}
+
+	if cfg.Style != "" {
+		if err := SetBoardStyle(cfg.Style); err != nil {
+			return err
+		}
+	}
+	if cfg.Theme != "" && colorMode != ModeOff {
+		return SetTheme(cfg.Theme)
+	}
+	return nil
+}
+
+// SetTheme selects the active board theme
+func SetTheme(name string) error {
+	name = strings.ToLower(name)
+	if _, ok := themes[name]; !ok {
+		return fmt.Errorf("unknown theme: %s", name)
+	}
+	themeName = name
+	return nil
+}
+
+// CurrentTheme returns the active theme name
+func CurrentTheme() string {
+	return themeName
+}
+
+// ThemeNames returns registered theme names in sorted order
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mode returns the detected terminal color mode
+func Mode() ColorMode {
+	return colorMode
+}
+
+// themeColor returns the foreground escape sequence for a role spec in the active theme
+func themeColor(spec string) string {
+	return themeEscape(spec, false)
+}
+
+// themeBackground returns the background escape sequence for a role spec in the active theme
+func themeBackground(spec string) string {
+	return themeEscape(spec, true)
+}
+
+func themeEscape(spec string, background bool) string {
+	if colorMode == ModeOff || themeName == "off" || spec == "" {
+		return ""
+	}
+	rgb, ok := parseSpec(spec)
+	if !ok {
+		return ""
+	}
+
+	// Extended sequences select background with 48, basic codes are offset by 10
+	extended, offset := 38, 0
+	if background {
+		extended, offset = 48, 10
+	}
+
+	switch {
+	case colorMode == ModeTrueColor && rgb.kind == specHex:
+		return fmt.Sprintf("\033[%d;2;%d;%d;%dm", extended, rgb.r, rgb.g, rgb.b)
+	case colorMode >= Mode256 && rgb.kind == specIndex:
+		return fmt.Sprintf("\033[%d;5;%dm", extended, rgb.index)
+	case colorMode >= Mode256 && rgb.kind == specHex:
+		return fmt.Sprintf("\033[%d;5;%dm", extended, nearest256(rgb.r, rgb.g, rgb.b))
+	case rgb.kind == specName:
+		return fmt.Sprintf("\033[%dm", rgb.index+offset)
+	default:
+		return fmt.Sprintf("\033[%dm", nearest16(rgb.r, rgb.g, rgb.b)+offset)
+	}
+}
+
+const (
+	specName = iota
+	specIndex
+	specHex
+)
+
+// colorSpec is a parsed color spec with its RGB approximation
+type colorSpec struct {
+	kind    int
+	index   int // ANSI code for names, palette index for 256 colors
+	r, g, b int
+}
+
+func parseSpec(spec string) (colorSpec, bool) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		return colorSpec{}, true
+	}
+
+	if strings.HasPrefix(spec, "#") && len(spec) == 7 {
+		v, err := strconv.ParseUint(spec[1:], 16, 32)
+		if err != nil {
+			return colorSpec{}, false
+		}
+		return colorSpec{kind: specHex, r: int(v >> 16 & 0xff), g: int(v >> 8 & 0xff), b: int(v & 0xff)}, true
+	}
+
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 0 || n > 255 {
+			return colorSpec{}, false
+		}
+		r, g, b := palette256(n)
+		return colorSpec{kind: specIndex, index: n, r: r, g: g, b: b}, true
+	}
+
+	for _, c := range basic16 {
+		if c.name == spec {
+			return colorSpec{kind: specName, index: c.code, r: c.r, g: c.g, b: c.b}, true
+		}
+	}
+	return colorSpec{}, false
+}
+
+// palette256 returns the approximate RGB value of a 256-color palette index
+func palette256(n int) (int, int, int) {
+	switch {
+	case n < 16:
+		c := basic16[n]
+		return c.r, c.g, c.b
+	case n < 232:
+		levels := []int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return levels[n/36], levels[n/6%6], levels[n%6]
+	default:
+		v := 8 + (n-232)*10
+		return v, v, v
+	}
+}
+
+// nearest256 maps RGB to the closest color cube or grayscale index
+func nearest256(r, g, b int) int {
+	best, bestDist := 16, -1
+	for n := 16; n < 256; n++ {
+		pr, pg, pb := palette256(n)
+		if d := distance(r, g, b, pr, pg, pb); bestDist < 0 || d < bestDist {
+			best, bestDist = n, d
+		}
+	}
+	return best
+}
+
+// nearest16 maps RGB to the closest ANSI 16-color code
+func nearest16(r, g, b int) int {
+	best, bestDist := 37, -1
+	for _, c := range basic16 {
+		if d := distance(r, g, b, c.r, c.g, c.b); bestDist < 0 || d < bestDist {
+			best, bestDist = c.code, d
+		}
+	}
+	return best
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return dr*dr + dg*dg + db*db
+}
@@ -1,9 +1,18 @@
 package session
 
 import (
+	"context"
+	"time"
+
 	"chess/internal/client/api"
 )
 
+// Computer move polling defaults, the total wait is extended for long searches
+const (
+	DefaultPollInterval = 200 * time.Millisecond
+	DefaultPollAttempts = 50
+)
+
 // Session maintains client state and configuration
 type Session struct {
 	APIBaseURL    string
@@ -14,11 +23,30 @@ type Session struct {
 	LastMoveCount int
 	Client        *api.Client
 	Verbose       bool
+	AutoSavePath  string        // PGN path written on exit when a game is active, empty disables
+	PollInterval  time.Duration // Delay between polls when long-polling is unavailable
+	PollAttempts  int           // Minimum polls before giving up on a computer move
 	// Game state for prompt
 	CurrentGameState *api.GameResponse
 	PlayerColor      string // "w", "b", or ""
+	// History review, active while ViewGame matches CurrentGame
+	ViewGame string
+	ViewPly  int
+	// Cancelled when the running command is interrupted
+	ctx context.Context
 }
 
+// Context returns the context of the running command, never nil
+func (s *Session) Context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// SetContext sets the context requests of the running command use
+func (s *Session) SetContext(ctx context.Context) { s.ctx = ctx }
+
 // Session interface implementation
 func (s *Session) GetAPIBaseURL() string      { return s.APIBaseURL }
 func (s *Session) SetAPIBaseURL(url string)   { s.APIBaseURL = url }
@@ -40,4 +68,5 @@ func (s *Session) SetGameState(game any) {
 	}
 }
 func (s *Session) SetPlayerColor(color string) { s.PlayerColor = color }
-func (s *Session) GetPlayerColor() string      { return s.PlayerColor }
\ No newline at end of file
+func (s *Session) GetPlayerColor() string      { return s.PlayerColor }
+func (s *Session) IsReviewing() bool           { return s.ViewGame != "" && s.ViewGame == s.CurrentGame }
\ No newline at end of file
@@ -0,0 +1,89 @@
+// Package config loads flag values from a JSON configuration file so long
+// command lines can be kept in one place.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Apply sets every flag named in the JSON object at path that was not given
+// on the command line, so explicit flags win over the file
+// Keys are flag names without the dash, lists are joined with commas
+// Unknown keys and values the flag rejects are reported together
+func Apply(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		return fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		if key == "config" || fs.Lookup(key) == nil {
+			problems = append(problems, fmt.Sprintf("unknown key %q", key))
+			continue
+		}
+		if explicit[key] {
+			continue
+		}
+		value, err := flagValue(values[key])
+		if err == nil {
+			err = fs.Set(key, value)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config %s: %s", path, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// flagValue converts a JSON value to the string form flags parse
+func flagValue(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return fmt.Sprint(v), nil
+	case json.Number:
+		return v.String(), nil
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("list items must be strings")
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", v)
+	}
+}
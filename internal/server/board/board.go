@@ -78,26 +78,51 @@ func ParseFEN(fen string) (*Board, error) {
 	return b, nil
 }
 
+// ASCIIOptions controls ToASCII rendering, the zero value gives the default layout
+type ASCIIOptions struct {
+	NoCoords bool // Omit the a-h and 1-8 labels
+	Empty    byte // Empty square character, 0 for '.'
+}
+
 // ToASCII creates an ASCII representation of the board
-func (b *Board) ToASCII() string {
+func (b *Board) ToASCII(opts ASCIIOptions) string {
+	empty := opts.Empty
+	if empty == 0 {
+		empty = '.'
+	}
+
 	var sb strings.Builder
-	sb.WriteString("  a b c d e f g h\n")
+	if !opts.NoCoords {
+		sb.WriteString("  a b c d e f g h\n")
+	}
 
 	for r := 0; r < 8; r++ {
-		sb.WriteString(fmt.Sprintf("%d ", 8-r))
+		if !opts.NoCoords {
+			sb.WriteString(fmt.Sprintf("%d ", 8-r))
+		}
 		for f := 0; f < 8; f++ {
 			square := fmt.Sprintf("%c%c", 'a'+f, '8'-r)
 			piece := b.GetPieceAt(square)
 
 			if piece == 0 {
-				sb.WriteString(". ")
+				piece = empty
+			}
+			if opts.NoCoords && f == 7 {
+				sb.WriteByte(piece)
 			} else {
 				sb.WriteString(fmt.Sprintf("%c ", piece))
 			}
 		}
-		sb.WriteString(fmt.Sprintf("%d\n", 8-r))
+		if !opts.NoCoords {
+			sb.WriteString(fmt.Sprintf("%d", 8-r))
+		}
+		if r < 7 || !opts.NoCoords {
+			sb.WriteString("\n")
+		}
+	}
+	if !opts.NoCoords {
+		sb.WriteString("  a b c d e f g h")
 	}
-	sb.WriteString("  a b c d e f g h")
 
 	return sb.String()
 }
@@ -116,4 +141,33 @@ func (b *Board) GetPieceAt(square string) byte {
 	file := square[0] - 'a'
 	rank := '8' - square[1]
 	return b.squares[rank][file]
+}
+
+// ColorAt returns the color of the piece on square, false for an empty or invalid square
+func (b *Board) ColorAt(square string) (core.Color, bool) {
+	piece := b.GetPieceAt(square)
+	if piece == 0 {
+		return 0, false
+	}
+	return pieceColor(piece), true
+}
+
+// FullMove returns the fullmove number from the position
+func (b *Board) FullMove() int {
+	return b.fullmove
+}
+
+// HalfMove returns the halfmove clock, plies since the last capture or pawn move
+func (b *Board) HalfMove() int {
+	return b.halfmove
+}
+
+// Castling returns the castling availability field, "-" if neither side can castle
+func (b *Board) Castling() string {
+	return b.castling
+}
+
+// EnPassant returns the en passant target square, "-" if there is none
+func (b *Board) EnPassant() string {
+	return b.enPassant
 }
\ No newline at end of file
@@ -0,0 +1,552 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+
+	"chess/internal/server/core"
+)
+
+var (
+	knightOffsets = [8][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}
+	kingOffsets   = [8][2]int{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+	rookDirs      = [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	bishopDirs    = [4][2]int{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+)
+
+// move is an internal move representation, rank index 0 is rank 8
+type move struct {
+	fromR, fromF int
+	toR, toF     int
+	promotion    byte // lowercase piece letter or 0
+}
+
+// UCI returns the move in UCI notation
+func (m move) UCI() string {
+	s := squareName(m.fromR, m.fromF) + squareName(m.toR, m.toF)
+	if m.promotion != 0 {
+		s += string(m.promotion)
+	}
+	return s
+}
+
+// squareName converts board indices to algebraic square name
+func squareName(r, f int) string {
+	return fmt.Sprintf("%c%c", 'a'+f, '8'-r)
+}
+
+// parseSquare converts an algebraic square name to board indices
+func parseSquare(s string) (r, f int, ok bool) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return 0, 0, false
+	}
+	return int('8' - s[1]), int(s[0] - 'a'), true
+}
+
+// parseUCI converts a UCI move string to the internal representation
+func parseUCI(uci string) (move, error) {
+	if len(uci) < 4 || len(uci) > 5 {
+		return move{}, fmt.Errorf("invalid UCI move: %s", uci)
+	}
+	fromR, fromF, ok1 := parseSquare(uci[0:2])
+	toR, toF, ok2 := parseSquare(uci[2:4])
+	if !ok1 || !ok2 {
+		return move{}, fmt.Errorf("invalid UCI move: %s", uci)
+	}
+	m := move{fromR: fromR, fromF: fromF, toR: toR, toF: toF}
+	if len(uci) == 5 {
+		switch uci[4] {
+		case 'q', 'r', 'b', 'n':
+			m.promotion = uci[4]
+		default:
+			return move{}, fmt.Errorf("invalid promotion piece in move: %s", uci)
+		}
+	}
+	return m, nil
+}
+
+func onBoard(r, f int) bool {
+	return r >= 0 && r < 8 && f >= 0 && f < 8
+}
+
+func isWhitePiece(p byte) bool {
+	return p >= 'A' && p <= 'Z'
+}
+
+func pieceColor(p byte) core.Color {
+	if p == 0 {
+		return 0
+	}
+	if isWhitePiece(p) {
+		return core.ColorWhite
+	}
+	return core.ColorBlack
+}
+
+// pieceFor returns the piece letter in the case matching the color
+func pieceFor(kind byte, c core.Color) byte {
+	kind = lower(kind)
+	if c == core.ColorWhite {
+		return kind - 'a' + 'A'
+	}
+	return kind
+}
+
+func lower(p byte) byte {
+	if p >= 'A' && p <= 'Z' {
+		return p - 'A' + 'a'
+	}
+	return p
+}
+
+// Clone returns a deep copy of the board
+func (b *Board) Clone() *Board {
+	c := *b
+	return &c
+}
+
+// ToFEN serializes the board to FEN notation
+func (b *Board) ToFEN() string {
+	var sb strings.Builder
+	for r := 0; r < 8; r++ {
+		empty := 0
+		for f := 0; f < 8; f++ {
+			p := b.squares[r][f]
+			if p == 0 {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteByte(byte('0' + empty))
+				empty = 0
+			}
+			sb.WriteByte(p)
+		}
+		if empty > 0 {
+			sb.WriteByte(byte('0' + empty))
+		}
+		if r < 7 {
+			sb.WriteByte('/')
+		}
+	}
+
+	castling := b.castling
+	if castling == "" {
+		castling = "-"
+	}
+	enPassant := b.enPassant
+	if enPassant == "" {
+		enPassant = "-"
+	}
+
+	return fmt.Sprintf("%s %s %s %s %d %d", sb.String(), b.turn, castling, enPassant, b.halfmove, b.fullmove)
+}
+
+// findKing locates the king of the given color
+func (b *Board) findKing(c core.Color) (int, int, bool) {
+	king := pieceFor('k', c)
+	for r := 0; r < 8; r++ {
+		for f := 0; f < 8; f++ {
+			if b.squares[r][f] == king {
+				return r, f, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// isAttacked reports whether the square is attacked by any piece of the given color
+func (b *Board) isAttacked(r, f int, by core.Color) bool {
+	// Pawns attack diagonally forward, so look one rank behind from the target
+	pawnRow := r + 1
+	if by == core.ColorBlack {
+		pawnRow = r - 1
+	}
+	for _, df := range []int{-1, 1} {
+		if onBoard(pawnRow, f+df) && b.squares[pawnRow][f+df] == pieceFor('p', by) {
+			return true
+		}
+	}
+
+	for _, o := range knightOffsets {
+		if onBoard(r+o[0], f+o[1]) && b.squares[r+o[0]][f+o[1]] == pieceFor('n', by) {
+			return true
+		}
+	}
+
+	for _, o := range kingOffsets {
+		if onBoard(r+o[0], f+o[1]) && b.squares[r+o[0]][f+o[1]] == pieceFor('k', by) {
+			return true
+		}
+	}
+
+	if b.slidingAttack(r, f, by, rookDirs[:], 'r') || b.slidingAttack(r, f, by, bishopDirs[:], 'b') {
+		return true
+	}
+
+	return false
+}
+
+// slidingAttack scans rays for an attacking slider of the given kind or a queen
+func (b *Board) slidingAttack(r, f int, by core.Color, dirs [][2]int, kind byte) bool {
+	slider := pieceFor(kind, by)
+	queen := pieceFor('q', by)
+	for _, d := range dirs {
+		for cr, cf := r+d[0], f+d[1]; onBoard(cr, cf); cr, cf = cr+d[0], cf+d[1] {
+			p := b.squares[cr][cf]
+			if p == 0 {
+				continue
+			}
+			if p == slider || p == queen {
+				return true
+			}
+			break
+		}
+	}
+	return false
+}
+
+// InCheck reports whether the side to move is in check
+func (b *Board) InCheck() bool {
+	r, f, ok := b.findKing(b.turn)
+	if !ok {
+		return false
+	}
+	return b.isAttacked(r, f, core.OppositeColor(b.turn))
+}
+
+// pseudoMoves generates moves for the side to move without checking king safety
+func (b *Board) pseudoMoves() []move {
+	var moves []move
+	us := b.turn
+
+	for r := 0; r < 8; r++ {
+		for f := 0; f < 8; f++ {
+			p := b.squares[r][f]
+			if p == 0 || pieceColor(p) != us {
+				continue
+			}
+			switch lower(p) {
+			case 'p':
+				moves = b.pawnMoves(moves, r, f)
+			case 'n':
+				moves = b.stepMoves(moves, r, f, knightOffsets[:])
+			case 'k':
+				moves = b.stepMoves(moves, r, f, kingOffsets[:])
+				moves = b.castlingMoves(moves, r, f)
+			case 'b':
+				moves = b.slideMoves(moves, r, f, bishopDirs[:])
+			case 'r':
+				moves = b.slideMoves(moves, r, f, rookDirs[:])
+			case 'q':
+				moves = b.slideMoves(moves, r, f, bishopDirs[:])
+				moves = b.slideMoves(moves, r, f, rookDirs[:])
+			}
+		}
+	}
+	return moves
+}
+
+func (b *Board) pawnMoves(moves []move, r, f int) []move {
+	us := b.turn
+	dir, startRow, lastRow := -1, 6, 0
+	if us == core.ColorBlack {
+		dir, startRow, lastRow = 1, 1, 7
+	}
+
+	add := func(toR, toF int) {
+		if toR == lastRow {
+			for _, promo := range []byte{'q', 'r', 'b', 'n'} {
+				moves = append(moves, move{r, f, toR, toF, promo})
+			}
+			return
+		}
+		moves = append(moves, move{r, f, toR, toF, 0})
+	}
+
+	// Pushes
+	if onBoard(r+dir, f) && b.squares[r+dir][f] == 0 {
+		add(r+dir, f)
+		if r == startRow && b.squares[r+2*dir][f] == 0 {
+			add(r+2*dir, f)
+		}
+	}
+
+	// Captures including en passant
+	epR, epF, hasEP := parseSquare(b.enPassant)
+	for _, df := range []int{-1, 1} {
+		toR, toF := r+dir, f+df
+		if !onBoard(toR, toF) {
+			continue
+		}
+		target := b.squares[toR][toF]
+		if target != 0 && pieceColor(target) != us {
+			add(toR, toF)
+		} else if hasEP && toR == epR && toF == epF {
+			add(toR, toF)
+		}
+	}
+	return moves
+}
+
+func (b *Board) stepMoves(moves []move, r, f int, offsets [][2]int) []move {
+	for _, o := range offsets {
+		toR, toF := r+o[0], f+o[1]
+		if !onBoard(toR, toF) {
+			continue
+		}
+		target := b.squares[toR][toF]
+		if target == 0 || pieceColor(target) != b.turn {
+			moves = append(moves, move{r, f, toR, toF, 0})
+		}
+	}
+	return moves
+}
+
+func (b *Board) slideMoves(moves []move, r, f int, dirs [][2]int) []move {
+	for _, d := range dirs {
+		for toR, toF := r+d[0], f+d[1]; onBoard(toR, toF); toR, toF = toR+d[0], toF+d[1] {
+			target := b.squares[toR][toF]
+			if target == 0 {
+				moves = append(moves, move{r, f, toR, toF, 0})
+				continue
+			}
+			if pieceColor(target) != b.turn {
+				moves = append(moves, move{r, f, toR, toF, 0})
+			}
+			break
+		}
+	}
+	return moves
+}
+
+// castlingMoves adds castling moves; the king may not castle out of, through, or into check
+func (b *Board) castlingMoves(moves []move, r, f int) []move {
+	us := b.turn
+	them := core.OppositeColor(us)
+	homeRow, kingSide, queenSide := 7, byte('K'), byte('Q')
+	if us == core.ColorBlack {
+		homeRow, kingSide, queenSide = 0, 'k', 'q'
+	}
+	if r != homeRow || f != 4 || b.isAttacked(r, f, them) {
+		return moves
+	}
+
+	rook := pieceFor('r', us)
+	if strings.IndexByte(b.castling, kingSide) >= 0 && b.squares[r][7] == rook &&
+		b.squares[r][5] == 0 && b.squares[r][6] == 0 &&
+		!b.isAttacked(r, 5, them) && !b.isAttacked(r, 6, them) {
+		moves = append(moves, move{r, f, r, 6, 0})
+	}
+	if strings.IndexByte(b.castling, queenSide) >= 0 && b.squares[r][0] == rook &&
+		b.squares[r][1] == 0 && b.squares[r][2] == 0 && b.squares[r][3] == 0 &&
+		!b.isAttacked(r, 3, them) && !b.isAttacked(r, 2, them) {
+		moves = append(moves, move{r, f, r, 2, 0})
+	}
+	return moves
+}
+
+// legalMoves filters pseudo-legal moves that leave the mover's king attacked
+func (b *Board) legalMoves() []move {
+	var legal []move
+	for _, m := range b.pseudoMoves() {
+		next := b.play(m)
+		r, f, ok := next.findKing(b.turn)
+		if ok && next.isAttacked(r, f, next.turn) {
+			continue
+		}
+		legal = append(legal, m)
+	}
+	return legal
+}
+
+// LegalMoves returns all legal moves for the side to move in UCI notation
+func (b *Board) LegalMoves() []string {
+	legal := b.legalMoves()
+	moves := make([]string, len(legal))
+	for i, m := range legal {
+		moves[i] = m.UCI()
+	}
+	return moves
+}
+
+// HasLegalMoves reports whether the side to move has at least one legal move
+func (b *Board) HasLegalMoves() bool {
+	return len(b.legalMoves()) > 0
+}
+
+// IsCheckmate reports whether the side to move is checkmated
+func (b *Board) IsCheckmate() bool {
+	return b.InCheck() && !b.HasLegalMoves()
+}
+
+// IsStalemate reports whether the side to move has no legal moves and is not in check
+func (b *Board) IsStalemate() bool {
+	return !b.InCheck() && !b.HasLegalMoves()
+}
+
+// IsInsufficientMaterial reports whether neither side can deliver mate: bare kings,
+// a single minor piece, or only bishops all standing on squares of one color
+func (b *Board) IsInsufficientMaterial() bool {
+	minors := 0
+	bishopColors := [2]bool{}
+	knights := false
+	for r := 0; r < 8; r++ {
+		for f := 0; f < 8; f++ {
+			switch lower(b.squares[r][f]) {
+			case 0, 'k':
+			case 'b':
+				minors++
+				bishopColors[(r+f)%2] = true
+			case 'n':
+				minors++
+				knights = true
+			default:
+				return false // Pawn, rook or queen
+			}
+		}
+	}
+	if minors <= 1 {
+		return true
+	}
+	return !knights && bishopColors[0] != bishopColors[1]
+}
+
+// ApplyMove validates a UCI move against the position and returns the resulting board
+func (b *Board) ApplyMove(uci string) (*Board, error) {
+	m, err := parseUCI(strings.ToLower(uci))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, legal := range b.legalMoves() {
+		if legal == m {
+			next := b.play(m)
+			// Keep the en passant target only when a capture is actually
+			// available, matching the FEN produced by the engine
+			if next.enPassant != "-" && !next.hasEnPassantCapture() {
+				next.enPassant = "-"
+			}
+			return next, nil
+		}
+	}
+	return nil, fmt.Errorf("illegal move: %s", uci)
+}
+
+// IsPromotion reports whether uci moves a pawn of the side to move to the last
+// rank, regardless of a promotion piece or the move's legality
+func (b *Board) IsPromotion(uci string) bool {
+	if len(uci) < 4 {
+		return false
+	}
+	fromR, fromF, ok1 := parseSquare(uci[0:2])
+	toR, _, ok2 := parseSquare(uci[2:4])
+	if !ok1 || !ok2 {
+		return false
+	}
+	lastRank := 0
+	if b.turn == core.ColorBlack {
+		lastRank = 7
+	}
+	return b.squares[fromR][fromF] == pieceFor('p', b.turn) && toR == lastRank
+}
+
+// play executes a move without legality checks and returns the new board
+func (b *Board) play(m move) *Board {
+	next := b.Clone()
+	us := b.turn
+	piece := b.squares[m.fromR][m.fromF]
+	captured := b.squares[m.toR][m.toF]
+	kind := lower(piece)
+
+	next.squares[m.fromR][m.fromF] = 0
+	next.squares[m.toR][m.toF] = piece
+
+	// En passant capture removes the pawn behind the target square
+	if kind == 'p' && m.fromF != m.toF && captured == 0 {
+		next.squares[m.fromR][m.toF] = 0
+		captured = pieceFor('p', core.OppositeColor(us))
+	}
+
+	// Promotion
+	if m.promotion != 0 {
+		next.squares[m.toR][m.toF] = pieceFor(m.promotion, us)
+	}
+
+	// Castling moves the rook alongside the king
+	if kind == 'k' && m.toF-m.fromF == 2 {
+		next.squares[m.fromR][5] = next.squares[m.fromR][7]
+		next.squares[m.fromR][7] = 0
+	} else if kind == 'k' && m.fromF-m.toF == 2 {
+		next.squares[m.fromR][3] = next.squares[m.fromR][0]
+		next.squares[m.fromR][0] = 0
+	}
+
+	next.castling = updateCastling(b.castling, m, kind, us)
+
+	// Halfmove clock resets on pawn moves and captures
+	if kind == 'p' || captured != 0 {
+		next.halfmove = 0
+	} else {
+		next.halfmove = b.halfmove + 1
+	}
+	if us == core.ColorBlack {
+		next.fullmove = b.fullmove + 1
+	}
+	next.turn = core.OppositeColor(us)
+
+	next.enPassant = "-"
+	if kind == 'p' && (m.toR-m.fromR == 2 || m.fromR-m.toR == 2) {
+		next.enPassant = squareName((m.fromR+m.toR)/2, m.fromF)
+	}
+
+	return next
+}
+
+// hasEnPassantCapture reports whether the side to move can legally capture en passant
+func (b *Board) hasEnPassantCapture() bool {
+	epR, epF, ok := parseSquare(b.enPassant)
+	if !ok {
+		return false
+	}
+	for _, m := range b.legalMoves() {
+		if m.toR == epR && m.toF == epF && lower(b.squares[m.fromR][m.fromF]) == 'p' {
+			return true
+		}
+	}
+	return false
+}
+
+// updateCastling removes rights lost by king or rook moves and rook captures
+func updateCastling(rights string, m move, kind byte, us core.Color) string {
+	if rights == "-" {
+		return "-"
+	}
+	remove := func(flags string) {
+		for i := 0; i < len(flags); i++ {
+			rights = strings.ReplaceAll(rights, string(flags[i]), "")
+		}
+	}
+
+	if kind == 'k' {
+		if us == core.ColorWhite {
+			remove("KQ")
+		} else {
+			remove("kq")
+		}
+	}
+
+	// Any move from or to a rook home square clears that right
+	corners := map[string]string{"h1": "K", "a1": "Q", "h8": "k", "a8": "q"}
+	if flag, ok := corners[squareName(m.fromR, m.fromF)]; ok {
+		remove(flag)
+	}
+	if flag, ok := corners[squareName(m.toR, m.toF)]; ok {
+		remove(flag)
+	}
+
+	if rights == "" {
+		return "-"
+	}
+	return rights
+}
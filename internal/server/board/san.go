@@ -0,0 +1,129 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToSAN converts a legal UCI move to Standard Algebraic Notation for this position
+func (b *Board) ToSAN(uci string) (string, error) {
+	m, err := parseUCI(strings.ToLower(uci))
+	if err != nil {
+		return "", err
+	}
+
+	legal := b.legalMoves()
+	found := false
+	for _, lm := range legal {
+		if lm == m {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("illegal move: %s", uci)
+	}
+
+	piece := b.squares[m.fromR][m.fromF]
+	kind := lower(piece)
+	var sb strings.Builder
+
+	switch {
+	case kind == 'k' && m.toF-m.fromF == 2:
+		sb.WriteString("O-O")
+	case kind == 'k' && m.fromF-m.toF == 2:
+		sb.WriteString("O-O-O")
+	case kind == 'p':
+		if m.fromF != m.toF {
+			sb.WriteByte(byte('a' + m.fromF))
+			sb.WriteByte('x')
+		}
+		sb.WriteString(squareName(m.toR, m.toF))
+		if m.promotion != 0 {
+			sb.WriteByte('=')
+			sb.WriteByte(m.promotion - 'a' + 'A')
+		}
+	default:
+		sb.WriteByte(kind - 'a' + 'A')
+		sb.WriteString(b.disambiguation(m, legal))
+		if b.squares[m.toR][m.toF] != 0 {
+			sb.WriteByte('x')
+		}
+		sb.WriteString(squareName(m.toR, m.toF))
+	}
+
+	next := b.play(m)
+	if next.InCheck() {
+		if next.HasLegalMoves() {
+			sb.WriteByte('+')
+		} else {
+			sb.WriteByte('#')
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// disambiguation returns the file, rank, or square needed to distinguish
+// the move from other legal moves of the same piece type to the same square
+func (b *Board) disambiguation(m move, legal []move) string {
+	piece := b.squares[m.fromR][m.fromF]
+	sameFile, sameRank, ambiguous := false, false, false
+
+	for _, other := range legal {
+		if other.toR != m.toR || other.toF != m.toF {
+			continue
+		}
+		if other.fromR == m.fromR && other.fromF == m.fromF {
+			continue
+		}
+		if b.squares[other.fromR][other.fromF] != piece {
+			continue
+		}
+		ambiguous = true
+		if other.fromF == m.fromF {
+			sameFile = true
+		}
+		if other.fromR == m.fromR {
+			sameRank = true
+		}
+	}
+
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return string(rune('a' + m.fromF))
+	case !sameRank:
+		return string(rune('8' - m.fromR))
+	default:
+		return squareName(m.fromR, m.fromF)
+	}
+}
+
+// ParseSAN resolves a SAN move in this position to UCI notation
+// Check, mate and annotation suffixes are ignored, "0-0" castling is accepted
+func (b *Board) ParseSAN(san string) (string, error) {
+	want := normalizeSAN(san)
+	if want == "" {
+		return "", fmt.Errorf("empty SAN move")
+	}
+
+	for _, m := range b.legalMoves() {
+		candidate, err := b.ToSAN(m.UCI())
+		if err != nil {
+			continue
+		}
+		if normalizeSAN(candidate) == want {
+			return m.UCI(), nil
+		}
+	}
+	return "", fmt.Errorf("illegal move: %s", san)
+}
+
+// normalizeSAN strips suffixes and promotion '=' so equivalent SAN forms compare equal
+func normalizeSAN(san string) string {
+	san = strings.TrimRight(strings.TrimSpace(san), "+#!?")
+	san = strings.ReplaceAll(san, "0", "O")
+	return strings.ReplaceAll(san, "=", "")
+}
@@ -0,0 +1,34 @@
+package board
+
+import "testing"
+
+func TestToSAN(t *testing.T) {
+	tests := []struct {
+		name string
+		fen  string
+		uci  string
+		want string
+	}{
+		{"knight file disambiguation", "4k3/8/8/8/8/5N2/8/1N2K3 w - - 0 1", "b1d2", "Nbd2"},
+		{"knight rank disambiguation", "4k3/8/8/8/8/1N6/8/1N2K3 w - - 0 1", "b1d2", "N1d2"},
+		{"en passant", "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6", "exd6"},
+		{"promotion with check", "8/4P3/8/8/k7/8/8/4K3 w - - 0 1", "e7e8q", "e8=Q+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			got, err := b.ToSAN(tt.uci)
+			if err != nil || got != tt.want {
+				t.Fatalf("ToSAN(%s) = %q, %v, want %q", tt.uci, got, err, tt.want)
+			}
+			back, err := b.ParseSAN(got)
+			if err != nil || back != tt.uci {
+				t.Fatalf("ParseSAN(%s) = %q, %v, want %s", got, back, err, tt.uci)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package board
+
+import (
+	"fmt"
+
+	"chess/internal/server/core"
+)
+
+// castlingSquares maps each castling right to its rank index and rook file,
+// the king must stand on the e-file of the same rank
+var castlingSquares = map[byte][2]int{
+	'K': {7, 7},
+	'Q': {7, 0},
+	'k': {0, 7},
+	'q': {0, 0},
+}
+
+// ValidatePosition checks that the position could occur in a game: one king
+// per side, no pawns on the back ranks, the side not to move is not in check,
+// and the castling rights and en passant square match the pieces on the board
+func (b *Board) ValidatePosition() error {
+	for _, c := range []core.Color{core.ColorWhite, core.ColorBlack} {
+		if n := b.count(pieceFor('k', c)); n != 1 {
+			return fmt.Errorf("%s has %d kings, expected 1", c.Name(), n)
+		}
+	}
+
+	for _, r := range []int{0, 7} {
+		for f := 0; f < 8; f++ {
+			if lower(b.squares[r][f]) == 'p' {
+				return fmt.Errorf("pawn on %s, pawns cannot stand on rank 1 or 8", squareName(r, f))
+			}
+		}
+	}
+
+	them := core.OppositeColor(b.turn)
+	if r, f, _ := b.findKing(them); b.isAttacked(r, f, b.turn) {
+		return fmt.Errorf("%s is in check but it is %s to move", them.Name(), b.turn.Name())
+	}
+
+	if b.castling != "-" {
+		for i := 0; i < len(b.castling); i++ {
+			right := b.castling[i]
+			sq, ok := castlingSquares[right]
+			if !ok {
+				return fmt.Errorf("unknown castling right %q", right)
+			}
+			c := core.ColorWhite
+			if right >= 'a' {
+				c = core.ColorBlack
+			}
+			if b.squares[sq[0]][4] != pieceFor('k', c) {
+				return fmt.Errorf("castling right %c without a king on %s", right, squareName(sq[0], 4))
+			}
+			if b.squares[sq[0]][sq[1]] != pieceFor('r', c) {
+				return fmt.Errorf("castling right %c without a rook on %s", right, squareName(sq[0], sq[1]))
+			}
+		}
+	}
+
+	if b.enPassant != "-" {
+		r, f, ok := parseSquare(b.enPassant)
+		// The double pushed pawn belongs to the side not to move and passed over the target square
+		pawnR, fromR, wantR := r+1, r-1, 2
+		if b.turn == core.ColorBlack {
+			pawnR, fromR, wantR = r-1, r+1, 5
+		}
+		if !ok || r != wantR {
+			return fmt.Errorf("en passant square %s is not on rank %d", b.enPassant, 8-wantR)
+		}
+		if b.squares[r][f] != 0 || b.squares[fromR][f] != 0 || b.squares[pawnR][f] != pieceFor('p', them) {
+			return fmt.Errorf("en passant square %s does not follow a %s double pawn push", b.enPassant, them.Name())
+		}
+	}
+
+	return nil
+}
+
+// count returns the number of squares holding piece
+func (b *Board) count(piece byte) int {
+	n := 0
+	for r := 0; r < 8; r++ {
+		for f := 0; f < 8; f++ {
+			if b.squares[r][f] == piece {
+				n++
+			}
+		}
+	}
+	return n
+}
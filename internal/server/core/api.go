@@ -1,36 +1,67 @@
 package core
 
+import "regexp"
+
+// UCI moves: [a-h][1-8][a-h][1-8][qrbn]?
+// Examples: e2e4 / e1g1 (castle) / a7a8q (promotion)
+var uciPattern = regexp.MustCompile(`^[a-h][1-8][a-h][1-8][qrbn]?$`)
+
+// IsUCIMove reports whether a move is in lowercase UCI long algebraic notation
+func IsUCIMove(move string) bool {
+	return uciPattern.MatchString(move)
+}
+
 // Request types
 
 type CreateGameRequest struct {
-	White PlayerConfig `json:"white" validate:"required"`
-	Black PlayerConfig `json:"black" validate:"required"`
-	FEN   string       `json:"fen,omitempty" validate:"omitempty,max=100"`
+	White     PlayerConfig `json:"white" validate:"required"`
+	Black     PlayerConfig `json:"black" validate:"required"`
+	FEN       string       `json:"fen,omitempty" validate:"omitempty,max=256"` // Processor enforces the configured limit
+	AutoStart bool         `json:"autoStart,omitempty"`                        // Start the computer's move when it moves first
+
+	WaitFirstMove bool `json:"-"` // Set from the waitFirstMove query, hold the response until the first computer move
 }
 
 type ConfigurePlayersRequest struct {
-	White PlayerConfig `json:"white" validate:"required"`
-	Black PlayerConfig `json:"black" validate:"required"`
+	White             PlayerConfig `json:"white" validate:"required"`
+	Black             PlayerConfig `json:"black" validate:"required"`
+	ExpectedMoveCount *int         `json:"expectedMoveCount,omitempty" validate:"omitempty,min=0"` // Rejected with conflict if the game has a different move count
 }
 
 type MoveRequest struct {
-	Move string `json:"move" validate:"required,min=4,max=5"` // "cccc" for computer move, 4-5 chars for UCI moves
+	Move              string `json:"move" validate:"required,uci|eq=cccc"`                   // "cccc" for computer move, otherwise UCI
+	ExpectedMoveCount *int   `json:"expectedMoveCount,omitempty" validate:"omitempty,min=0"` // Rejected with conflict if the game has a different move count
+}
+
+type BatchMoveRequest struct {
+	Moves             []string `json:"moves" validate:"required,min=1,max=100,dive,uci"` // Human moves applied in order
+	ExpectedMoveCount *int     `json:"expectedMoveCount,omitempty" validate:"omitempty,min=0"`
 }
 
+// MaxUndoCount is the most moves one undo request may take back, larger
+// rewinds are better served by reset or a new game from a FEN
+const MaxUndoCount = 100
+
 type UndoRequest struct {
-	Count int `json:"count" validate:"required,min=1,max=300"` // Max based on longest games in history (272), theoretical max 5949
+	Count int   `json:"count" validate:"omitempty,min=1,max=100"` // Zero or absent undoes one move, see MaxUndoCount
+	Smart *bool `json:"smart,omitempty"`                          // Human vs computer: also undo the computer's reply, default true
+	Force bool  `json:"force,omitempty"`                          // Reopen a finished game when the server runs with strict undo
 }
 
 // Response types
 
 type GameResponse struct {
-	GameID   string          `json:"gameId"`
-	FEN      string          `json:"fen"`
-	Turn     string          `json:"turn"`  // "w" or "b"
-	State    string          `json:"state"` // "ongoing", "white_wins", etc
-	Moves    []string        `json:"moves"`
-	Players  PlayersResponse `json:"players"`
-	LastMove *MoveInfo       `json:"lastMove,omitempty"`
+	GameID         string          `json:"gameId"`
+	FEN            string          `json:"fen"`
+	InitialFEN     string          `json:"initialFen"`       // Starting position, for replaying moves
+	Turn           string          `json:"turn"`             // "w" or "b"
+	State          string          `json:"state"`            // "ongoing", "white_wins", etc
+	Reason         string          `json:"reason,omitempty"` // Why the game ended, e.g. "checkmate"
+	Moves          []string        `json:"moves"`
+	MoveCount      int             `json:"moveCount"` // Total moves, Moves may hold a window of them
+	Players        PlayersResponse `json:"players"`
+	LastMove       *MoveInfo       `json:"lastMove,omitempty"`
+	PreviousGameID string          `json:"previousGameId,omitempty"` // Source game of a rematch
 }
 
 type MoveInfo struct {
@@ -40,9 +71,104 @@ type MoveInfo struct {
 	Depth       int    `json:"depth,omitempty"`
 }
 
+type AnalysisResponse struct {
+	GameID string        `json:"gameId"`
+	Depth  int           `json:"depth"`
+	Plies  []PlyAnalysis `json:"plies"`
+}
+
+type PlyAnalysis struct {
+	Ply         int    `json:"ply"` // 1-based half-move index
+	Move        string `json:"move"`
+	PlayerColor string `json:"playerColor"`         // "w" or "b"
+	BestMove    string `json:"bestMove"`            // Engine choice in the position before the move
+	Score       int    `json:"score"`               // Evaluation after the move in centipawns, positive favors white
+	MateIn      int    `json:"mateIn,omitempty"`    // Mate distance after the move, positive favors white
+	Loss        int    `json:"loss"`                // Centipawns the mover gave up compared to the position before
+	WDL         []int  `json:"wdl,omitempty"`       // White win, draw, black win per mille after the move, with tablebases
+	Tablebase   bool   `json:"tablebase,omitempty"` // Evaluation after the move was resolved by tablebases
+}
+
+// GradeRequest submits a candidate move to compare with the engine's choice
+type GradeRequest struct {
+	Move string `json:"move" validate:"required,uci"`
+}
+
+// Move grades by centipawn loss against the engine's best move
+const (
+	GradeBest       = "best"
+	GradeGood       = "good"
+	GradeInaccuracy = "inaccuracy"
+	GradeMistake    = "mistake"
+	GradeBlunder    = "blunder"
+)
+
+// GradeResponse compares a candidate move with the engine's best move,
+// scores are in centipawns from the side to move
+type GradeResponse struct {
+	Move      string `json:"move"`
+	BestMove  string `json:"bestMove"`
+	Score     int    `json:"score"`     // Evaluation after the candidate move
+	BestScore int    `json:"bestScore"` // Evaluation with the best move
+	Loss      int    `json:"loss"`      // Centipawns given up compared to the best move
+	Grade     string `json:"grade"`     // best, good, inaccuracy, mistake or blunder
+	Depth     int    `json:"depth"`
+}
+
+// SearchInfo is a progress event of a running computer move search,
+// the score is in centipawns from the side to move
+type SearchInfo struct {
+	Depth int      `json:"depth"`
+	Score int      `json:"score"`
+	Mate  int      `json:"mate,omitempty"` // Moves to mate, negative when the side to move is mated
+	PV    []string `json:"pv,omitempty"`   // Expected continuation in UCI notation
+}
+
+// SearchEnd closes a search stream with the move the engine chose
+type SearchEnd struct {
+	Move string `json:"move"`
+}
+
+// MoveWindow selects a slice of the move history, a zero Limit means all moves from From
+type MoveWindow struct {
+	From  int
+	Limit int
+}
+
+// TurnResponse is the minimal state needed to poll for a player's turn
+type TurnResponse struct {
+	Turn      string `json:"turn"` // "w" or "b"
+	State     string `json:"state"`
+	MoveCount int    `json:"moveCount"`
+}
+
+// VersionResponse identifies the server build, API and engine
+type VersionResponse struct {
+	Server string `json:"server"`
+	API    string `json:"api"`
+	Engine string `json:"engine,omitempty"` // Omitted without an engine
+}
+
+// ScoresheetLine is one numbered move pair in SAN
+// White is omitted on the first line when Black moved first, Black on an unanswered last move
+type ScoresheetLine struct {
+	Number int    `json:"number"`
+	White  string `json:"white,omitempty"`
+	Black  string `json:"black,omitempty"`
+}
+
 type BoardResponse struct {
-	FEN   string `json:"fen"`
-	Board string `json:"board"` // ASCII representation
+	FEN       string `json:"fen"`
+	Board     string `json:"board"`     // ASCII representation
+	Castling  string `json:"castling"`  // FEN castling field, e.g. "KQkq" or "-"
+	EnPassant string `json:"enPassant"` // Target square, e.g. "e3", or "-"
+	HalfMove  int    `json:"halfmove"`  // Plies since the last capture or pawn move
+	FullMove  int    `json:"fullmove"`
+
+	// Set only when requested with status=true
+	InCheck  *bool  `json:"inCheck,omitempty"` // Side to move is in check
+	GameOver *bool  `json:"gameOver,omitempty"`
+	Reason   string `json:"reason,omitempty"` // How the game ended, e.g. "checkmate"
 }
 
 type ErrorResponse struct {
@@ -13,4 +13,6 @@ const (
 	ErrInternalError     = "INTERNAL_ERROR"
 	ErrResourceLimit     = "RESOURCE_LIMIT"
 	ErrUnauthorized      = "UNAUTHORIZED"
+	ErrMoveConflict      = "MOVE_CONFLICT"
+	ErrEngineSaturated   = "ENGINE_SATURATED"
 )
\ No newline at end of file
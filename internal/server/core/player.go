@@ -13,19 +13,24 @@ const (
 
 // Player is the complete game entity with all state
 type Player struct {
-	ID         string     `json:"id"`
-	Color      Color      `json:"color"`
-	Type       PlayerType `json:"type"`
-	Level      int        `json:"level,omitempty"`      // Only for computer
-	SearchTime int        `json:"searchTime,omitempty"` // Only for computer
-	ClaimedBy  string     `json:"claimedBy,omitempty"`  // UserID that claimed this slot
+	ID                        string            `json:"id"`
+	Color                     Color             `json:"color"`
+	Type                      PlayerType        `json:"type"`
+	Level                     int               `json:"level,omitempty"`                     // Only for computer
+	SearchTime                int               `json:"searchTime,omitempty"`                // Only for computer
+	ClaimedBy                 string            `json:"claimedBy,omitempty"`                 // UserID that claimed this slot
+	EngineOptions             map[string]string `json:"engineOptions,omitempty"`             // Only for computer, extra UCI options
+	SearchTimeHandicapPercent int               `json:"searchTimeHandicapPercent,omitempty"` // Only for computer, share of SearchTime used
+	EffectiveSearchTime       int               `json:"effectiveSearchTime,omitempty"`       // Only for computer, SearchTime after the handicap
 }
 
 // PlayerConfig for API requests and configuration
 type PlayerConfig struct {
-	Type       PlayerType `json:"type" validate:"required,oneof=1 2"`
-	Level      int        `json:"level,omitempty" validate:"omitempty,min=0,max=20"`
-	SearchTime int        `json:"searchTime,omitempty" validate:"omitempty,min=100,max=10000"` // Processor sets the min value
+	Type                      PlayerType        `json:"type" validate:"required,oneof=1 2"`
+	Level                     int               `json:"level,omitempty" validate:"omitempty,min=0,max=20"`
+	SearchTime                int               `json:"searchTime,omitempty" validate:"omitempty,min=100,max=10000"`                                // Processor sets the min value
+	EngineOptions             map[string]string `json:"engineOptions,omitempty" validate:"omitempty,max=16,dive,keys,min=1,max=64,endkeys,max=256"` // Processor checks the allowlist
+	SearchTimeHandicapPercent int               `json:"searchTimeHandicapPercent,omitempty" validate:"omitempty,min=10,max=300"`                    // 50 halves the computer's time
 }
 
 // PlayersResponse for API responses
@@ -45,11 +50,23 @@ func NewPlayer(config PlayerConfig, color Color) *Player {
 	if config.Type == PlayerComputer {
 		player.Level = config.Level
 		player.SearchTime = config.SearchTime
+		player.EngineOptions = config.EngineOptions
+		player.SearchTimeHandicapPercent = config.SearchTimeHandicapPercent
+		player.EffectiveSearchTime = player.HandicappedSearchTime()
 	}
 
 	return player
 }
 
+// HandicappedSearchTime returns SearchTime scaled by the handicap percentage,
+// unchanged when no handicap is set
+func (p *Player) HandicappedSearchTime() int {
+	if p.SearchTimeHandicapPercent == 0 {
+		return p.SearchTime
+	}
+	return max(p.SearchTime*p.SearchTimeHandicapPercent/100, 1)
+}
+
 // IsClaimed returns true if this slot has been claimed by a user
 func (p *Player) IsClaimed() bool {
 	return p.ClaimedBy != ""
@@ -77,6 +94,18 @@ func (c Color) String() string {
 	}
 }
 
+// Name returns "white" or "black" for messages
+func (c Color) Name() string {
+	switch c {
+	case ColorWhite:
+		return "white"
+	case ColorBlack:
+		return "black"
+	default:
+		return "none"
+	}
+}
+
 func OppositeColor(c Color) Color {
 	if c == ColorWhite {
 		return ColorBlack
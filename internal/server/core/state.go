@@ -10,6 +10,21 @@ const (
 	StateBlackWins
 	StateDraw
 	StateStalemate
+	StateAborted // Terminated without a result
+)
+
+// Reasons reported alongside a terminal state
+const (
+	ReasonCheckmate            = "checkmate"
+	ReasonStalemate            = "stalemate"
+	ReasonInsufficientMaterial = "insufficient_material"
+	ReasonAborted              = "aborted"
+	ReasonResignation          = "resignation"
+	ReasonAgreement            = "agreement" // Draw agreed by both players
+	ReasonTimeout              = "timeout"
+	ReasonDeadPosition         = "dead_position" // Computer game drawn as unwinnable
+	ReasonRepetition           = "threefold_repetition"
+	ReasonFiftyMove            = "fifty_move_rule" // 50 moves by each side without a capture or pawn move
 )
 
 func (s State) String() string {
@@ -26,9 +41,50 @@ func (s State) String() string {
 		return "draw"
 	case StateStalemate:
 		return "stalemate"
+	case StateAborted:
+		return "aborted"
 	case StateOngoing:
 		return "ongoing"
 	default:
 		return "unknown"
 	}
+}
+
+// IsGameOver reports whether the state is terminal (game has a result)
+func (s State) IsGameOver() bool {
+	switch s {
+	case StateWhiteWins, StateBlackWins, StateDraw, StateStalemate, StateAborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Result returns the PGN result notation for the state, "*" if unfinished or aborted
+func (s State) Result() string {
+	switch s {
+	case StateWhiteWins:
+		return "1-0"
+	case StateBlackWins:
+		return "0-1"
+	case StateDraw, StateStalemate:
+		return "1/2-1/2"
+	default:
+		return "*"
+	}
+}
+
+// StateFromResult maps PGN result notation back to a state
+// Stalemate cannot be distinguished from other draws and maps to StateDraw
+func StateFromResult(result string) State {
+	switch result {
+	case "1-0":
+		return StateWhiteWins
+	case "0-1":
+		return StateBlackWins
+	case "1/2-1/2":
+		return StateDraw
+	default:
+		return StateOngoing
+	}
 }
\ No newline at end of file
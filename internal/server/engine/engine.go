@@ -2,22 +2,69 @@ package engine
 
 import (
 	"bufio"
-	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
 )
 
-const enginePath = "stockfish"
+const depthSearchTimeout = 10 * time.Second
 
+// maxLineSize bounds one line of engine output, info lines with a long
+// principal variation exceed the 64 KiB scanner default
+const maxLineSize = 1024 * 1024
+
+// infoBuffer is the number of progress updates queued for a slow onInfo
+// callback, later updates are dropped until it catches up
+const infoBuffer = 64
+
+// ErrTimeout is returned when the engine does not answer in time
+var ErrTimeout = errors.New("engine timeout")
+
+// enginePath is the engine binary, looked up in PATH unless it contains a separator
+var enginePath = "stockfish"
+
+// positionSync makes SetPosition wait for readyok, see SetPositionSync
+var positionSync = true
+
+// SetPath sets the engine binary started by New, call before any engine starts
+func SetPath(path string) {
+	enginePath = path
+}
+
+// SetPositionSync controls whether SetPosition waits for the engine to
+// acknowledge the position with readyok, call before any engine starts
+// Disabling it saves a round trip per position on a lightly loaded engine
+func SetPositionSync(enabled bool) {
+	positionSync = enabled
+}
+
+// UCI drives an engine process
+// A single goroutine reads engine output, each command and its response run
+// under call so concurrent callers cannot consume each other's replies
 type UCI struct {
-	cmd    *exec.Cmd
-	stdin  io.WriteCloser
-	stdout *bufio.Scanner
-	mu     sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Scanner
+	lines   chan string // Engine output, closed when reading stops
+	readErr error       // Why reading stopped, valid once lines is closed
+	mu      sync.Mutex
+	call    sync.Mutex
+	sync    bool // Wait for readyok after each position
+	// Option defaults reported during the uci handshake, keyed by lowercase name
+	defaults map[string]string
+	// Identification and option names reported during the uci handshake
+	name    string
+	author  string
+	options []string
+	// Depth ceiling added to every search, zero for none
+	maxDepth int
 }
 
 type SearchResult struct {
@@ -26,6 +73,41 @@ type SearchResult struct {
 	Depth    int
 	IsMate   bool
 	MateIn   int
+	WDL      []int    // Win/draw/loss per mille for the side to move, only with UCI_ShowWDL
+	TBHits   int      // Tablebase probes that resolved a position
+	PV       []string // Principal variation of the last info line, in UCI notation
+}
+
+// Engine is the engine control used by the processor and its queue,
+// implemented by UCI and by in-memory engines in tests
+type Engine interface {
+	NewGame()
+	SetPosition(fen string, moves []string)
+	GetFEN() (string, error)
+	Search(timeMs int) (*SearchResult, error)
+	SearchWithProgress(timeMs int, onInfo func(SearchResult)) (*SearchResult, error)
+	SearchDepth(depth int) (*SearchResult, error)
+	SetSkillLevel(level int)
+	SetOption(name, value string) error
+	SetSyzygyPath(path string) error
+	SetMaxDepth(depth int)
+	OptionDefault(name string) (string, bool)
+	SupportsOption(name string) bool
+	Name() string
+	Ping() error
+	Close() error
+}
+
+// Factory starts a new engine instance
+type Factory func() (Engine, error)
+
+// NewEngine starts the configured UCI binary, the default Factory
+func NewEngine() (Engine, error) {
+	uci, err := New()
+	if err != nil {
+		return nil, err // Avoid a non-nil interface holding a nil *UCI
+	}
+	return uci, nil
 }
 
 func New() (*UCI, error) {
@@ -45,11 +127,8 @@ func New() (*UCI, error) {
 		return nil, fmt.Errorf("failed to start engine: %v", err)
 	}
 
-	uci := &UCI{
-		cmd:    cmd,
-		stdin:  stdin,
-		stdout: bufio.NewScanner(stdout),
-	}
+	uci := attach(stdin, stdout)
+	uci.cmd = cmd
 
 	if err := uci.initialize(); err != nil {
 		uci.Close()
@@ -59,6 +138,22 @@ func New() (*UCI, error) {
 	return uci, nil
 }
 
+// attach wires a UCI to engine input and output and starts reading the output
+func attach(stdin io.WriteCloser, stdout io.Reader) *UCI {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	uci := &UCI{
+		stdin:    stdin,
+		stdout:   scanner,
+		lines:    make(chan string, 256),
+		sync:     positionSync,
+		defaults: make(map[string]string),
+	}
+	go uci.readLoop()
+	return uci
+}
+
 // SetSkillLevel sets the Stockfish skill level (0-20)
 func (u *UCI) SetSkillLevel(level int) {
 	if level < 0 {
@@ -69,90 +164,220 @@ func (u *UCI) SetSkillLevel(level int) {
 	u.sendCommand(fmt.Sprintf("setoption name Skill Level value %d", level))
 }
 
-// Get FEN from Stockfish's debug ('d') command
-func (u *UCI) GetFEN() (string, error) {
-	u.sendCommand("d")
+// SetOption sets a UCI option, rejecting input that could inject engine commands
+func (u *UCI) SetOption(name, value string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("empty option name")
+	}
+	if strings.ContainsFunc(name+value, unicode.IsControl) {
+		return fmt.Errorf("option %q contains control characters", name)
+	}
+	// The engine splits name and value on the "value" token
+	if slices.Contains(strings.Fields(strings.ToLower(name)), "value") {
+		return fmt.Errorf("option %q contains reserved word", name)
+	}
+	u.sendCommand(fmt.Sprintf("setoption name %s value %s", name, value))
+	return nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+// SetSyzygyPath points the engine at Syzygy tablebase directories, separated
+// by ':' (';' on Windows), the engine falls back to search for missing tables
+func (u *UCI) SetSyzygyPath(path string) error {
+	return u.SetOption("SyzygyPath", path)
+}
 
-	done := make(chan string, 1)
-	go func() {
-		for u.stdout.Scan() {
-			line := u.stdout.Text()
-			if strings.HasPrefix(line, "Fen: ") {
-				done <- strings.TrimPrefix(line, "Fen: ")
-				return
-			}
-		}
-		done <- ""
-	}()
+// Name returns the name and version the engine identified itself with
+func (u *UCI) Name() string {
+	return u.name
+}
 
-	select {
-	case fen := <-done:
-		if fen == "" {
-			return "", fmt.Errorf("failed to get FEN from engine")
+// Author returns the engine authors from the uci handshake
+func (u *UCI) Author() string {
+	return u.author
+}
+
+// Options returns the names of the options the engine supports, in the order reported
+func (u *UCI) Options() []string {
+	return slices.Clone(u.options)
+}
+
+// SupportsOption reports whether the engine listed an option, matched case-insensitively
+func (u *UCI) SupportsOption(name string) bool {
+	return slices.ContainsFunc(u.options, func(option string) bool {
+		return strings.EqualFold(option, strings.TrimSpace(name))
+	})
+}
+
+// OptionDefault returns the default the engine reported for an option
+func (u *UCI) OptionDefault(name string) (string, bool) {
+	value, ok := u.defaults[strings.ToLower(strings.TrimSpace(name))]
+	return value, ok
+}
+
+// recordHandshakeLine captures identification, option names and defaults
+// from the engine's reply to "uci"
+func (u *UCI) recordHandshakeLine(line string) {
+	if name, ok := strings.CutPrefix(line, "id name "); ok {
+		u.name = name
+		return
+	}
+	if author, ok := strings.CutPrefix(line, "id author "); ok {
+		u.author = author
+		return
+	}
+	if rest, ok := strings.CutPrefix(line, "option name "); ok {
+		if name, _, ok := strings.Cut(rest, " type "); ok {
+			u.options = append(u.options, name)
 		}
-		return fen, nil
-	case <-ctx.Done():
-		return "", fmt.Errorf("timeout getting FEN")
+		u.recordOptionDefault(line)
 	}
 }
 
-func (u *UCI) initialize() error {
-	u.sendCommand("uci")
+// recordOptionDefault parses "option name <name> type <t> default <value> ..." lines
+func (u *UCI) recordOptionDefault(line string) {
+	rest, ok := strings.CutPrefix(line, "option name ")
+	if !ok {
+		return
+	}
+	name, rest, ok := strings.Cut(rest, " type ")
+	if !ok {
+		return
+	}
+	_, value, ok := strings.Cut(rest, " default ")
+	if !ok {
+		return // Buttons have no default
+	}
+	// Spin and combo options list bounds and choices after the default
+	if strings.HasPrefix(rest, "spin ") || strings.HasPrefix(rest, "combo ") {
+		value, _, _ = strings.Cut(value, " ")
+	}
+	u.defaults[strings.ToLower(name)] = value
+}
 
-	// Wait for uciok with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// Get FEN from Stockfish's debug ('d') command
+func (u *UCI) GetFEN() (string, error) {
+	u.call.Lock()
+	defer u.call.Unlock()
 
-	done := make(chan bool)
-	go func() {
-		for u.stdout.Scan() {
-			if u.stdout.Text() == "uciok" {
-				done <- true
-				return
-			}
-		}
-		done <- false
-	}()
+	u.discardPending()
+	u.sendCommand("d")
 
-	select {
-	case success := <-done:
-		if !success {
-			return fmt.Errorf("engine closed unexpectedly")
+	var fen string
+	err := u.await(2*time.Second, func(line string) bool {
+		var found bool
+		fen, found = strings.CutPrefix(line, "Fen: ")
+		return found
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting FEN: %w", err)
+	}
+	if fen == "" {
+		return "", fmt.Errorf("failed to get FEN from engine")
+	}
+	return fen, nil
+}
+
+func (u *UCI) initialize() error {
+	u.call.Lock()
+	defer u.call.Unlock()
+
+	u.sendCommand("uci")
+	err := u.await(5*time.Second, func(line string) bool {
+		if line == "uciok" {
+			return true
 		}
-	case <-ctx.Done():
-		return fmt.Errorf("timeout waiting for uciok")
+		u.recordHandshakeLine(line)
+		return false
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for uciok: %w", err)
 	}
 
 	u.sendCommand("isready")
 	return u.waitReady()
 }
 
+// waitReady waits for the reply to isready, caller must hold call
 func (u *UCI) waitReady() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	err := u.await(5*time.Second, func(line string) bool {
+		return line == "readyok"
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for readyok: %w", err)
+	}
+	return nil
+}
 
-	done := make(chan error)
-	go func() {
-		for u.stdout.Scan() {
-			if u.stdout.Text() == "readyok" {
-				done <- nil
+// readLoop forwards engine output lines until the engine exits or a line is too long
+func (u *UCI) readLoop() {
+	for u.stdout.Scan() {
+		u.lines <- u.stdout.Text()
+	}
+	u.readErr = u.scanErr()
+	close(u.lines)
+}
+
+// await consumes engine output until handle reports the awaited line,
+// failing with ErrTimeout or the reason reading stopped
+func (u *UCI) await(timeout time.Duration, handle func(line string) bool) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-u.lines:
+			if !ok {
+				return u.readErr
+			}
+			if handle(line) {
+				return nil
+			}
+		case <-timer.C:
+			return ErrTimeout
+		}
+	}
+}
+
+// discardPending drops output left over from an earlier call that timed out
+func (u *UCI) discardPending() {
+	for {
+		select {
+		case _, ok := <-u.lines:
+			if !ok {
 				return
 			}
+		default:
+			return
 		}
-		done <- fmt.Errorf("engine closed unexpectedly")
-	}()
+	}
+}
 
-	select {
-	case err := <-done:
-		return err
-	case <-ctx.Done():
-		return fmt.Errorf("timeout waiting for readyok")
+// scanErr explains why reading engine output stopped, an over-long line
+// leaves the scanner unusable so the engine is treated as failed
+func (u *UCI) scanErr() error {
+	err := u.stdout.Err()
+	switch {
+	case errors.Is(err, bufio.ErrTooLong):
+		log.Printf("Engine output line exceeds %d bytes, engine unusable", maxLineSize)
+		return fmt.Errorf("engine output line too long: %w", err)
+	case err != nil:
+		return fmt.Errorf("reading engine output: %w", err)
+	default:
+		return fmt.Errorf("engine closed unexpectedly")
 	}
 }
 
+// Ping checks that the engine process still answers commands
+func (u *UCI) Ping() error {
+	u.call.Lock()
+	defer u.call.Unlock()
+
+	u.discardPending()
+	u.sendCommand("isready")
+	return u.waitReady()
+}
+
 func (u *UCI) sendCommand(cmd string) {
 	u.mu.Lock()
 	defer u.mu.Unlock()
@@ -160,80 +385,173 @@ func (u *UCI) sendCommand(cmd string) {
 }
 
 func (u *UCI) NewGame() {
+	u.call.Lock()
+	defer u.call.Unlock()
+
+	u.discardPending()
 	u.sendCommand("ucinewgame")
 	u.sendCommand("isready")
 	u.waitReady()
 }
 
+// SetPosition sets the position for the next search or FEN query, waiting
+// until the engine has processed it unless position sync is disabled
 func (u *UCI) SetPosition(fen string, moves []string) {
 	cmd := fmt.Sprintf("position fen %s", fen)
 	if len(moves) > 0 {
 		cmd += " moves " + strings.Join(moves, " ")
 	}
+	if !u.sync {
+		u.sendCommand(cmd)
+		return
+	}
+
+	u.call.Lock()
+	defer u.call.Unlock()
+
+	u.discardPending()
 	u.sendCommand(cmd)
+	u.sendCommand("isready")
+	if err := u.waitReady(); err != nil {
+		log.Printf("Engine did not acknowledge position: %v", err)
+	}
 }
 
 func (u *UCI) Search(timeMs int) (*SearchResult, error) {
-	u.sendCommand(fmt.Sprintf("go movetime %d", timeMs))
-
-	result := &SearchResult{}
+	return u.SearchWithProgress(timeMs, nil)
+}
 
+// SearchWithProgress searches like Search and passes the result so far to
+// onInfo after every info line carrying a score, onInfo may be nil
+// Updates are delivered in order on a separate goroutine, all before it returns
+func (u *UCI) SearchWithProgress(timeMs int, onInfo func(SearchResult)) (*SearchResult, error) {
+	goCmd := fmt.Sprintf("go movetime %d", timeMs)
+	if u.maxDepth > 0 {
+		// The engine stops at whichever limit it reaches first
+		goCmd += fmt.Sprintf(" depth %d", u.maxDepth)
+	}
 	// Add timeout protection (2x the search time + buffer)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeMs*2+1000)*time.Millisecond)
-	defer cancel()
+	return u.search(goCmd, time.Duration(timeMs*2+1000)*time.Millisecond, onInfo)
+}
 
-	done := make(chan error)
-	go func() {
-		for u.stdout.Scan() {
-			line := u.stdout.Text()
-
-			if strings.HasPrefix(line, "info ") {
-				fields := strings.Fields(line)
-				for i := 0; i < len(fields)-1; i++ {
-					switch fields[i] {
-					case "depth":
-						fmt.Sscanf(fields[i+1], "%d", &result.Depth)
-					case "cp":
-						fmt.Sscanf(fields[i+1], "%d", &result.Score)
-						result.IsMate = false
-					case "mate":
-						fmt.Sscanf(fields[i+1], "%d", &result.MateIn)
-						result.IsMate = true
-						// Convert mate score to centipawn equivalent for backwards compatibility
-						if result.MateIn > 0 {
-							result.Score = 100000 - result.MateIn
-						} else {
-							result.Score = -100000 - result.MateIn
+// SearchDepth searches to a fixed depth, giving comparable evaluations across positions
+func (u *UCI) SearchDepth(depth int) (*SearchResult, error) {
+	if u.maxDepth > 0 {
+		depth = min(depth, u.maxDepth)
+	}
+	return u.search(fmt.Sprintf("go depth %d", depth), depthSearchTimeout, nil)
+}
+
+// SetMaxDepth caps the depth of every later search, zero removes the cap
+func (u *UCI) SetMaxDepth(depth int) {
+	u.maxDepth = depth
+}
+
+func (u *UCI) search(goCmd string, timeout time.Duration, onInfo func(SearchResult)) (*SearchResult, error) {
+	u.call.Lock()
+	defer u.call.Unlock()
+
+	u.discardPending()
+	u.sendCommand(goCmd)
+
+	progress, stop := dispatchInfo(onInfo)
+	defer stop()
+
+	result := &SearchResult{}
+	err := u.await(timeout, func(line string) bool {
+		if strings.HasPrefix(line, "info ") {
+			fields := strings.Fields(line)
+			for i := 0; i < len(fields)-1; i++ {
+				switch fields[i] {
+				case "depth":
+					fmt.Sscanf(fields[i+1], "%d", &result.Depth)
+				case "cp":
+					fmt.Sscanf(fields[i+1], "%d", &result.Score)
+					result.IsMate = false
+				case "wdl":
+					if i+3 < len(fields) {
+						result.WDL = make([]int, 3)
+						for j := range result.WDL {
+							fmt.Sscanf(fields[i+1+j], "%d", &result.WDL[j])
 						}
 					}
+				case "tbhits":
+					fmt.Sscanf(fields[i+1], "%d", &result.TBHits)
+				case "pv":
+					// The variation runs to the end of the line
+					result.PV = slices.Clone(fields[i+1:])
+					i = len(fields)
+				case "mate":
+					fmt.Sscanf(fields[i+1], "%d", &result.MateIn)
+					result.IsMate = true
+					// Convert mate score to centipawn equivalent for backwards compatibility
+					if result.MateIn > 0 {
+						result.Score = 100000 - result.MateIn
+					} else {
+						result.Score = -100000 - result.MateIn
+					}
 				}
 			}
+			// Lines without a score only report the move being searched
+			if slices.Contains(fields, "score") {
+				progress(*result)
+			}
+		}
 
-			if strings.HasPrefix(line, "bestmove ") {
-				parts := strings.Fields(line)
-				if len(parts) >= 2 {
-					result.BestMove = parts[1]
-				}
-				done <- nil
-				return
+		if strings.HasPrefix(line, "bestmove ") {
+			parts := strings.Fields(line)
+			if len(parts) >= 2 {
+				result.BestMove = parts[1]
 			}
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return nil, fmt.Errorf("waiting for bestmove: %w", err)
+	}
+	return result, nil
+}
+
+// dispatchInfo runs onInfo on its own goroutine so a slow callback cannot
+// hold up reading engine output, stop waits for queued updates to be delivered
+func dispatchInfo(onInfo func(SearchResult)) (progress func(SearchResult), stop func()) {
+	if onInfo == nil {
+		return func(SearchResult) {}, func() {}
+	}
+
+	updates := make(chan SearchResult, infoBuffer)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for update := range updates {
+			onInfo(update)
 		}
-		done <- fmt.Errorf("engine closed unexpectedly")
 	}()
 
-	select {
-	case err := <-done:
-		if err != nil {
-			return nil, err
+	progress = func(r SearchResult) {
+		// The search keeps its slices, the callback gets its own copies
+		r.WDL = slices.Clone(r.WDL)
+		r.PV = slices.Clone(r.PV)
+		select {
+		case updates <- r:
+		default:
 		}
-		return result, nil
-	case <-ctx.Done():
-		return nil, fmt.Errorf("timeout waiting for bestmove")
 	}
+	stop = func() {
+		close(updates)
+		<-done
+	}
+	return progress, stop
 }
 
 func (u *UCI) Close() error {
 	u.sendCommand("quit")
+	// Keep the reader from blocking on output nobody waits for until the process exits
+	go func() {
+		for range u.lines {
+		}
+	}()
 	time.Sleep(100 * time.Millisecond)
 
 	// Try graceful shutdown first
@@ -0,0 +1,32 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestOverlongLineFailsPendingCall(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	t.Cleanup(func() {
+		inR.Close()
+		outR.Close()
+	})
+	go io.Copy(io.Discard, inR)
+
+	u := attach(inW, outR)
+
+	// The scanner stops once its buffer is full, closing outR unblocks the rest
+	go outW.Write(bytes.Repeat([]byte("x"), maxLineSize+1))
+
+	err := u.Ping()
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("Ping() = %v, want bufio.ErrTooLong", err)
+	}
+	if _, ok := <-u.lines; ok {
+		t.Fatal("output still open after an over-long line")
+	}
+}
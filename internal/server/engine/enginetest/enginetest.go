@@ -0,0 +1,213 @@
+// Package enginetest provides a deterministic in-memory engine so the
+// processor can be exercised without an engine binary
+package enginetest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"chess/internal/server/board"
+	"chess/internal/server/engine"
+)
+
+// EngineName is reported by Name, like the id name line of a UCI engine
+const EngineName = "enginetest"
+
+// Engine tracks positions with the native board and answers searches instantly
+// Search plays the next scripted move when it is legal in the position,
+// otherwise the first legal move, so games are reproducible
+type Engine struct {
+	mu       sync.Mutex
+	board    *board.Board // nil until a valid position is set
+	script   []string
+	options  map[string]string
+	skill    int
+	maxDepth int
+	closed   bool
+}
+
+// New returns an engine that plays the scripted UCI moves in order
+func New(script ...string) *Engine {
+	return &Engine{
+		script:  slices.Clone(script),
+		options: make(map[string]string),
+		skill:   20,
+	}
+}
+
+// Factory returns an engine.Factory whose engines each play their own copy of script
+func Factory(script ...string) engine.Factory {
+	return func() (engine.Engine, error) {
+		return New(script...), nil
+	}
+}
+
+// NewGame is a no-op, the engine keeps no state between positions
+func (e *Engine) NewGame() {}
+
+// SetPosition sets the position after moves, stopping at the first illegal
+// move as UCI engines do, an invalid FEN leaves no position
+func (e *Engine) SetPosition(fen string, moves []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		e.board = nil
+		return
+	}
+	for _, move := range moves {
+		next, err := b.ApplyMove(move)
+		if err != nil {
+			break
+		}
+		b = next
+	}
+	e.board = b
+}
+
+// GetFEN returns the current position
+func (e *Engine) GetFEN() (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.board == nil {
+		return "", fmt.Errorf("no position set")
+	}
+	return e.board.ToFEN(), nil
+}
+
+// Search returns the move for the current position, the time is ignored
+func (e *Engine) Search(timeMs int) (*engine.SearchResult, error) {
+	return e.search(1)
+}
+
+// SearchWithProgress reports the final result as the only progress update
+func (e *Engine) SearchWithProgress(timeMs int, onInfo func(engine.SearchResult)) (*engine.SearchResult, error) {
+	result, err := e.search(1)
+	if err == nil && onInfo != nil && result.BestMove != "(none)" {
+		progress := *result
+		progress.BestMove = ""
+		onInfo(progress)
+	}
+	return result, err
+}
+
+// SearchDepth returns the move for the current position, reporting the depth asked for
+func (e *Engine) SearchDepth(depth int) (*engine.SearchResult, error) {
+	return e.search(depth)
+}
+
+func (e *Engine) search(depth int) (*engine.SearchResult, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil, fmt.Errorf("engine closed")
+	}
+	if e.board == nil {
+		return nil, fmt.Errorf("no position set")
+	}
+	if e.maxDepth > 0 {
+		depth = min(depth, e.maxDepth)
+	}
+
+	legal := e.board.LegalMoves()
+	if len(legal) == 0 {
+		// Mirrors "bestmove (none)" with the mate flag from the last info line
+		return &engine.SearchResult{BestMove: "(none)", Depth: depth, IsMate: e.board.InCheck()}, nil
+	}
+
+	move := legal[0]
+	if len(e.script) > 0 {
+		next := e.script[0]
+		if slices.Contains(legal, next) {
+			move = next
+			e.script = e.script[1:]
+		}
+	}
+	return &engine.SearchResult{BestMove: move, Depth: depth}, nil
+}
+
+// SetSkillLevel records the level, it does not change the moves played
+func (e *Engine) SetSkillLevel(level int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.skill = min(max(level, 0), 20)
+}
+
+// SkillLevel returns the last level set
+func (e *Engine) SkillLevel() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.skill
+}
+
+// SetOption records an option, every name is accepted
+func (e *Engine) SetOption(name, value string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("empty option name")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.options[strings.ToLower(name)] = value
+	return nil
+}
+
+// Option returns the value last set for an option
+func (e *Engine) Option(name string) (string, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	value, ok := e.options[strings.ToLower(strings.TrimSpace(name))]
+	return value, ok
+}
+
+// SetSyzygyPath records the path as the SyzygyPath option
+func (e *Engine) SetSyzygyPath(path string) error {
+	return e.SetOption("SyzygyPath", path)
+}
+
+// SetMaxDepth caps the depth reported by searches
+func (e *Engine) SetMaxDepth(depth int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxDepth = depth
+}
+
+// OptionDefault reports no defaults, options set for one player are not reset
+func (e *Engine) OptionDefault(name string) (string, bool) {
+	return "", false
+}
+
+// SupportsOption accepts every option name
+func (e *Engine) SupportsOption(name string) bool {
+	return strings.TrimSpace(name) != ""
+}
+
+// Name returns EngineName
+func (e *Engine) Name() string {
+	return EngineName
+}
+
+// Ping fails once the engine is closed
+func (e *Engine) Ping() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return fmt.Errorf("engine closed")
+	}
+	return nil
+}
+
+// Close marks the engine closed, later searches fail
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	return nil
+}
+
+var _ engine.Engine = (*Engine)(nil)
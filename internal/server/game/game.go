@@ -2,6 +2,8 @@ package game
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"chess/internal/server/board"
 	"chess/internal/server/core"
@@ -25,10 +27,14 @@ type MoveResult struct {
 }
 
 type Game struct {
-	snapshots  []Snapshot                  `json:"snapshots"`
-	players    map[core.Color]*core.Player `json:"players"`
-	state      core.State                  `json:"state"`
-	lastResult *MoveResult                 `json:"lastResult,omitempty"`
+	snapshots      []Snapshot                  `json:"snapshots"`
+	players        map[core.Color]*core.Player `json:"players"`
+	state          core.State                  `json:"state"`
+	lastResult     *MoveResult                 `json:"lastResult,omitempty"`
+	previousGameID string                      // Source game when this game is a rematch
+	reason         string                      // Why the game ended, empty while it is not over
+	levelPlies     int                         // Consecutive engine moves evaluated at exactly 0.00
+	updatedAt      time.Time                   // Last move or state change
 }
 
 func New(initialFEN string, whitePlayer, blackPlayer *core.Player, startingTurnColor core.Color) *Game {
@@ -53,18 +59,45 @@ func New(initialFEN string, whitePlayer, blackPlayer *core.Player, startingTurnC
 			core.ColorWhite: whitePlayer,
 			core.ColorBlack: blackPlayer,
 		},
-		state: core.StateOngoing,
+		state:     core.StateOngoing,
+		updatedAt: time.Now(),
 	}
 }
 
+// UpdatedAt returns when the game last had a move or state change
+func (g *Game) UpdatedAt() time.Time {
+	return g.updatedAt
+}
+
+// SetLastResult records a move outcome and counts consecutive engine
+// evaluations of 0.00, any other move ends the streak
 func (g *Game) SetLastResult(result *MoveResult) {
 	g.lastResult = result
+	if result != nil && result.Depth > 0 && result.Score == 0 {
+		g.levelPlies++
+	} else {
+		g.levelPlies = 0
+	}
+}
+
+// LevelPlies returns how many engine moves in a row were evaluated at 0.00
+func (g *Game) LevelPlies() int {
+	return g.levelPlies
 }
 
 func (g *Game) LastResult() *MoveResult {
 	return g.lastResult
 }
 
+// SetPreviousGameID links the game to the one it is a rematch of
+func (g *Game) SetPreviousGameID(gameID string) {
+	g.previousGameID = gameID
+}
+
+func (g *Game) PreviousGameID() string {
+	return g.previousGameID
+}
+
 // CurrentSnapshot returns the latest game snapshot
 func (g *Game) CurrentSnapshot() Snapshot {
 	return g.snapshots[len(g.snapshots)-1]
@@ -96,6 +129,7 @@ func (g *Game) AddSnapshot(fen string, move string, nextTurnColor core.Color) {
 		NextTurnColor: nextTurnColor,
 		PlayerID:      nextPlayer.ID,
 	})
+	g.updatedAt = time.Now()
 }
 
 func (g *Game) UpdatePlayers(whitePlayer, blackPlayer *core.Player) {
@@ -122,9 +156,55 @@ func (g *Game) UndoMoves(count int) error {
 	g.snapshots = g.snapshots[:len(g.snapshots)-count]
 	g.state = core.StateOngoing // Reset game state when undoing
 	g.lastResult = nil          // Clear last result
+	g.reason = ""
+	g.levelPlies = 0
+	g.updatedAt = time.Now()
 	return nil
 }
 
+// Reset returns the game to its initial position, keeping players and the starting FEN
+func (g *Game) Reset() {
+	g.snapshots = g.snapshots[:1]
+	g.state = core.StateOngoing
+	g.lastResult = nil
+	g.reason = ""
+	g.levelPlies = 0
+	g.updatedAt = time.Now()
+}
+
+// RepetitionCount returns how often the current position occurred in the
+// game, read from the live snapshots so undone moves no longer count
+func (g *Game) RepetitionCount() int {
+	current := positionKey(g.CurrentFEN())
+	count := 0
+	for _, snap := range g.snapshots {
+		if positionKey(snap.FEN) == current {
+			count++
+		}
+	}
+	return count
+}
+
+// positionKey is the part of a FEN that identifies a position for
+// repetition: placement, turn, castling and en passant, without the clocks
+func positionKey(fen string) string {
+	fields := strings.Fields(fen)
+	if len(fields) > 4 {
+		fields = fields[:4]
+	}
+	return strings.Join(fields, " ")
+}
+
+// TurnAfter returns the side to move once the first plies moves have been played
+func (g *Game) TurnAfter(plies int) core.Color {
+	return g.snapshots[plies].NextTurnColor
+}
+
+// MoveCount returns the number of moves played without building the move list
+func (g *Game) MoveCount() int {
+	return len(g.snapshots) - 1
+}
+
 func (g *Game) Moves() []string {
 	moves := []string{}
 	for i := 1; i < len(g.snapshots); i++ {
@@ -139,8 +219,21 @@ func (g *Game) State() core.State {
 	return g.state
 }
 
+// SetState changes the state, clearing the end reason unless the game is over
 func (g *Game) SetState(s core.State) {
 	g.state = s
+	g.updatedAt = time.Now()
+	if !s.IsGameOver() {
+		g.reason = ""
+	}
+}
+
+func (g *Game) Reason() string {
+	return g.reason
+}
+
+func (g *Game) SetReason(reason string) {
+	g.reason = reason
 }
 
 func (g *Game) InitialFEN() string {
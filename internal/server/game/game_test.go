@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+
+	"chess/internal/server/board"
+	"chess/internal/server/core"
+)
+
+// newTestGame returns a game between two humans from the starting position
+func newTestGame() *Game {
+	return New(board.StartingFEN,
+		&core.Player{ID: "white", Color: core.ColorWhite, Type: core.PlayerHuman},
+		&core.Player{ID: "black", Color: core.ColorBlack, Type: core.PlayerHuman},
+		core.ColorWhite)
+}
+
+// play applies UCI moves to the game's current position with the native board
+func play(t *testing.T, g *Game, moves ...string) {
+	t.Helper()
+	for _, move := range moves {
+		b, err := board.ParseFEN(g.CurrentFEN())
+		if err != nil {
+			t.Fatalf("parse %q: %v", g.CurrentFEN(), err)
+		}
+		next, err := b.ApplyMove(move)
+		if err != nil {
+			t.Fatalf("apply %s: %v", move, err)
+		}
+		g.AddSnapshot(next.ToFEN(), move, next.Turn())
+	}
+}
+
+// knightDance returns both sides' knights to their squares, repeating the position
+var knightDance = []string{"g1f3", "g8f6", "f3g1", "f6g8"}
+
+func TestRepetitionCountFollowsUndo(t *testing.T) {
+	g := newTestGame()
+	if got := g.RepetitionCount(); got != 1 {
+		t.Fatalf("new game count = %d, want 1", got)
+	}
+
+	play(t, g, knightDance...)
+	play(t, g, knightDance...)
+	if got := g.RepetitionCount(); got != 3 {
+		t.Fatalf("after two knight dances count = %d, want 3", got)
+	}
+
+	// Undo truncates the snapshots, the position left on the board occurred twice
+	if err := g.UndoMoves(1); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if got := g.RepetitionCount(); got != 2 {
+		t.Fatalf("after undo count = %d, want 2", got)
+	}
+
+	// Playing the move again restores the third occurrence
+	play(t, g, "f6g8")
+	if got := g.RepetitionCount(); got != 3 {
+		t.Fatalf("after replay count = %d, want 3", got)
+	}
+}
+
+func TestRepetitionIgnoresMoveCounters(t *testing.T) {
+	g := newTestGame()
+	play(t, g, knightDance...)
+	play(t, g, knightDance...)
+
+	// Back at the start with both clocks advanced, the position still repeats
+	if g.CurrentFEN() == board.StartingFEN {
+		t.Fatalf("FEN %q should differ from the start in its move counters", g.CurrentFEN())
+	}
+	if got := g.RepetitionCount(); got != 3 {
+		t.Fatalf("count = %d, want 3", got)
+	}
+}
+
+func TestRepetitionComparesCastlingRights(t *testing.T) {
+	g := newTestGame()
+	play(t, g, "e2e4", "e7e5")
+	play(t, g, "e1e2", "e8e7", "e2e1", "e7e8")
+
+	// Same placement and side to move, but the king walks cost castling rights
+	if got := g.RepetitionCount(); got != 1 {
+		t.Fatalf("count = %d, want 1", got)
+	}
+}
@@ -47,6 +47,36 @@ type UserResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+// SessionResponse describes one of the user's sessions
+type SessionResponse struct {
+	SessionID string    `json:"sessionId"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	IPAddress string    `json:"ipAddress,omitempty"`
+	Current   bool      `json:"current"` // Session of the token making the request
+}
+
+// Bounds for client details stored with sessions, headers are client-controlled
+const (
+	maxUserAgentLength = 256
+	maxIPAddressLength = 64
+)
+
+// sessionMeta captures the client details stored with a new session, the
+// address matches the key the rate limiter uses for the client
+func sessionMeta(c *fiber.Ctx) service.SessionMeta {
+	userAgent := c.Get("User-Agent")
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	ip := clientIP(c)
+	if len(ip) > maxIPAddressLength {
+		ip = ip[:maxIPAddressLength]
+	}
+	return service.SessionMeta{UserAgent: userAgent, IPAddress: ip}
+}
+
 // RegisterHandler creates a new user account
 func (h *HTTPHandler) RegisterHandler(c *fiber.Ctx) error {
 	var req RegisterRequest
@@ -115,7 +145,7 @@ func (h *HTTPHandler) RegisterHandler(c *fiber.Ctx) error {
 	}
 
 	// Create session for new user
-	sessionID, err := h.svc.CreateUserSession(user.UserID)
+	sessionID, err := h.svc.CreateUserSession(user.UserID, sessionMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
 			Error: "failed to create session",
@@ -191,7 +221,7 @@ func (h *HTTPHandler) LoginHandler(c *fiber.Ctx) error {
 	req.Identifier = strings.ToLower(req.Identifier)
 
 	// Authenticate user and create session (invalidates previous session)
-	user, sessionID, err := h.svc.AuthenticateUser(req.Identifier, req.Password)
+	user, sessionID, err := h.svc.AuthenticateUser(req.Identifier, req.Password, sessionMeta(c))
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(core.ErrorResponse{
 			Error: "invalid credentials",
@@ -264,3 +294,59 @@ func (h *HTTPHandler) LogoutHandler(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"message": "logged out"})
 }
 
+// ListSessionsHandler returns the authenticated user's active sessions
+func (h *HTTPHandler) ListSessionsHandler(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	currentID, _ := c.Locals("sessionID").(string)
+
+	sessions, err := h.svc.ListUserSessions(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
+			Error: "failed to list sessions",
+			Code:  core.ErrInternalError,
+		})
+	}
+
+	resp := make([]SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, SessionResponse{
+			SessionID: s.SessionID,
+			CreatedAt: s.CreatedAt,
+			ExpiresAt: s.ExpiresAt,
+			UserAgent: s.UserAgent,
+			IPAddress: s.IPAddress,
+			Current:   s.SessionID == currentID,
+		})
+	}
+
+	return c.JSON(fiber.Map{"sessions": resp})
+}
+
+// RevokeSessionHandler ends one of the authenticated user's sessions
+func (h *HTTPHandler) RevokeSessionHandler(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+
+	sessionID := c.Params("sessionId")
+	if !isValidUUID(sessionID) {
+		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+			Error:   "invalid session ID format",
+			Code:    core.ErrInvalidRequest,
+			Details: "session ID must be a valid UUID",
+		})
+	}
+
+	if err := h.svc.RevokeUserSession(userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(core.ErrorResponse{
+				Error: "session not found",
+				Code:  core.ErrInvalidRequest,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
+			Error: "failed to revoke session",
+			Code:  core.ErrInternalError,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
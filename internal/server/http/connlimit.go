@@ -0,0 +1,115 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"chess/internal/server/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultMaxConnsPerIP bounds the long-poll and stream requests one client IP holds open
+const DefaultMaxConnsPerIP = 10
+
+// connLimiter counts the long-lived requests each client IP has in flight
+// Entries are removed when their count drops to zero, so the map only holds
+// clients with open requests
+type connLimiter struct {
+	max    int32
+	active map[string]*atomic.Int32
+	mu     sync.Mutex
+}
+
+func newConnLimiter(max int) *connLimiter {
+	return &connLimiter{
+		max:    int32(max),
+		active: make(map[string]*atomic.Int32),
+	}
+}
+
+// acquire takes a slot for ip, reporting false when it already holds max
+func (l *connLimiter) acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count, ok := l.active[ip]
+	if !ok {
+		count = new(atomic.Int32)
+		l.active[ip] = count
+	}
+	if count.Load() >= l.max {
+		return false
+	}
+	count.Add(1)
+	return true
+}
+
+// release frees a slot taken by acquire
+func (l *connLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if count, ok := l.active[ip]; ok && count.Add(-1) <= 0 {
+		delete(l.active, ip)
+	}
+}
+
+// handler limits the requests long selects as long-lived, others pass through
+// A handler that streams its body after returning takes over the slot with
+// streamRelease and frees it when the stream ends
+func (l *connLimiter) handler(long func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if l == nil || !long(c) {
+			return c.Next()
+		}
+
+		ip := clientIP(c)
+		if !l.acquire(ip) {
+			return c.Status(fiber.StatusTooManyRequests).JSON(core.ErrorResponse{
+				Error:   "too many open connections",
+				Code:    core.ErrRateLimitExceeded,
+				Details: fmt.Sprintf("%d concurrent long-poll or stream requests per client allowed", l.max),
+			})
+		}
+
+		var once sync.Once
+		release := func() { once.Do(func() { l.release(ip) }) }
+		c.Locals("connRelease", release)
+
+		err := c.Next()
+		if _, kept := c.Locals("connRelease").(func()); kept {
+			release()
+		}
+		return err
+	}
+}
+
+// streamRelease hands the connection slot of c to a body stream writer, which
+// must call the returned function when it finishes
+func streamRelease(c *fiber.Ctx) func() {
+	release, ok := c.Locals("connRelease").(func())
+	if !ok {
+		return func() {}
+	}
+	c.Locals("connRelease", nil)
+	return release
+}
+
+// isLongPoll reports whether a game request waits for the next move
+func isLongPoll(c *fiber.Ctx) bool {
+	return c.Query("wait") == "true"
+}
+
+// isFirstMoveWait reports whether a game create waits for the computer's first move
+func isFirstMoveWait(c *fiber.Ctx) bool {
+	wait, _ := strconv.ParseBool(c.Query("waitFirstMove"))
+	return wait
+}
+
+// always limits every request of a route
+func always(*fiber.Ctx) bool {
+	return true
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+
+	"chess/internal/server/core"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// gameFields are the JSON names of the GameResponse fields a client may select
+var gameFields = jsonFieldNames(reflect.TypeOf(core.GameResponse{}))
+
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fieldsValidator checks the fields query parameter before the request runs,
+// so a typo does not report an error after a move was already made
+func fieldsValidator(c *fiber.Ctx) error {
+	raw := c.Query("fields")
+	if raw == "" {
+		return c.Next()
+	}
+
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(gameFields, field) {
+			return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+				Error:   "invalid fields parameter",
+				Code:    core.ErrInvalidRequest,
+				Details: "unknown field " + field + ", valid fields: " + strings.Join(gameFields, ","),
+			})
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) > 0 {
+		c.Locals("fields", fields)
+	}
+	return c.Next()
+}
+
+// sendGame writes a game response, reduced to the fields the client selected
+// Other response types are written unchanged
+func sendGame(c *fiber.Ctx, status int, data any) error {
+	fields, _ := c.Locals("fields").([]string)
+	game, ok := data.(core.GameResponse)
+	if len(fields) == 0 || !ok {
+		return c.Status(status).JSON(data)
+	}
+
+	encoded, err := json.Marshal(game)
+	if err != nil {
+		return err
+	}
+	var full map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &full); err != nil {
+		return err
+	}
+
+	// Empty optional fields stay omitted as in the full response
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := full[field]; ok {
+			projected[field] = value
+		}
+	}
+	return c.Status(status).JSON(projected)
+}
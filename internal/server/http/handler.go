@@ -1,14 +1,19 @@
 package http
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
 
+	"chess/internal/server/board"
 	"chess/internal/server/core"
 	"chess/internal/server/processor"
 	"chess/internal/server/service"
+	"chess/internal/server/storage"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -17,7 +22,16 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
-const rateLimitRate = 10 // req/sec
+const (
+	rateLimitRate = 10 // req/sec
+	APIVersion    = "v1"
+)
+
+// Version is the server build version, set with -ldflags "-X chess/internal/server/http.Version=..."
+var Version = "dev"
+
+// DefaultLogSkipPaths are probe endpoints left out of the request log
+var DefaultLogSkipPaths = []string{"/health", "/metrics"}
 
 // HTTPHandler handles HTTP requests and routes them to the processor
 type HTTPHandler struct {
@@ -29,21 +43,35 @@ func NewHTTPHandler(proc *processor.Processor, svc *service.Service) *HTTPHandle
 	return &HTTPHandler{proc: proc, svc: svc}
 }
 
-func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool) *fiber.App {
+// NewFiberApp builds the application, rate limit counters persist in
+// limiterStore when it is non-nil and stay in memory otherwise
+// Requests to logSkipPaths are served but not logged, and each client IP may
+// hold maxConns long-poll and stream requests open at once, zero for no limit
+// X-Forwarded-For is only believed from trustedProxies, addresses or CIDR ranges
+func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool, limiterStore *storage.Store, logSkipPaths []string, maxConns int, trustedProxies []string) *fiber.App {
 	// Create handler
 	h := NewHTTPHandler(proc, svc)
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		ErrorHandler: customErrorHandler,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 35 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ErrorHandler:            customErrorHandler,
+		ReadTimeout:             15 * time.Second,
+		WriteTimeout:            35 * time.Second,
+		IdleTimeout:             60 * time.Second,
+		EnableTrustedProxyCheck: len(trustedProxies) > 0,
+		TrustedProxies:          trustedProxies,
 	})
 
 	// Global middleware (order matters)
 	app.Use(recover.New())
+	skipLog := make(map[string]bool, len(logSkipPaths))
+	for _, path := range logSkipPaths {
+		skipLog[path] = true
+	}
 	app.Use(logger.New(logger.Config{
+		Next: func(c *fiber.Ctx) bool {
+			return skipLog[c.Path()]
+		},
 		Format: "${time} ${status} ${method} ${path} ${latency}\n",
 	}))
 	app.Use(cors.New(cors.Config{
@@ -54,6 +82,7 @@ func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool)
 
 	// Health check (no rate limit)
 	app.Get("/health", h.Health)
+	app.Get("/metrics", h.Metrics)
 
 	// API v1 routes
 	api := app.Group("/api/v1")
@@ -65,6 +94,7 @@ func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool)
 	auth.Post("/register", limiter.New(limiter.Config{
 		Max:        5,
 		Expiration: 1 * time.Minute,
+		Storage:    limiterStorage(limiterStore, "register"),
 		KeyGenerator: func(c *fiber.Ctx) string {
 			return c.IP()
 		},
@@ -81,6 +111,7 @@ func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool)
 	auth.Post("/login", limiter.New(limiter.Config{
 		Max:        10,
 		Expiration: 1 * time.Minute,
+		Storage:    limiterStorage(limiterStore, "login"),
 		KeyGenerator: func(c *fiber.Ctx) string {
 			return c.IP()
 		},
@@ -102,23 +133,20 @@ func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool)
 	// Logout
 	auth.Post("/logout", AuthRequired(validateToken), h.LogoutHandler)
 
+	// Session management
+	auth.Get("/sessions", AuthRequired(validateToken), h.ListSessionsHandler)
+	auth.Delete("/sessions/:sessionId", AuthRequired(validateToken), h.RevokeSessionHandler)
+
 	// Game routes with standard rate limiting
 	maxReq := rateLimitRate
 	if devMode {
 		maxReq = rateLimitRate * 2
 	}
 	api.Use(limiter.New(limiter.Config{
-		Max:        maxReq,
-		Expiration: 1 * time.Second,
-		KeyGenerator: func(c *fiber.Ctx) string {
-			if xff := c.Get("X-Forwarded-For"); xff != "" {
-				if idx := strings.Index(xff, ","); idx != -1 {
-					return strings.TrimSpace(xff[:idx])
-				}
-				return xff
-			}
-			return c.IP()
-		},
+		Max:          maxReq,
+		Expiration:   1 * time.Second,
+		Storage:      limiterStorage(limiterStore, "api"),
+		KeyGenerator: clientIP,
 		LimitReached: func(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusTooManyRequests).JSON(core.ErrorResponse{
 				Error:   "rate limit exceeded",
@@ -128,24 +156,86 @@ func NewFiberApp(proc *processor.Processor, svc *service.Service, devMode bool)
 		},
 	}))
 
+	// Game ID validation ahead of body checks, so a malformed ID is reported the same way on every endpoint
+	api.Use("/games/:gameId", gameIDValidator)
+
+	// Response field selection is checked before any game changes
+	api.Use("/games", fieldsValidator)
+
 	// Content-Type validation for POST and PUT requests
 	api.Use(contentTypeValidator)
 
 	// Middleware validation for sanitization
 	api.Use(validationMiddleware)
 
+	api.Get("/version", h.GetVersion)
+
+	// Long-lived requests are bounded per client IP on top of the request rate
+	var conns *connLimiter
+	if maxConns > 0 {
+		conns = newConnLimiter(maxConns)
+	}
+
 	// Register game routes with auth middleware
-	api.Post("/games", OptionalAuth(validateToken), h.CreateGame) // Optional auth for player ID association
+	api.Post("/games", conns.handler(isFirstMoveWait), OptionalAuth(validateToken), h.CreateGame) // Optional auth for player ID association
 	api.Put("/games/:gameId/players", h.ConfigurePlayers)
-	api.Get("/games/:gameId", h.GetGame)
+	api.Get("/games/:gameId", conns.handler(isLongPoll), h.GetGame)
 	api.Delete("/games/:gameId", h.DeleteGame)
 	api.Post("/games/:gameId/moves", OptionalAuth(validateToken), h.MakeMove)
+	api.Post("/games/:gameId/moves/batch", OptionalAuth(validateToken), h.MakeMoves)
 	api.Post("/games/:gameId/undo", h.UndoMove)
+	api.Post("/games/:gameId/reset", h.ResetGame)
+	api.Post("/games/:gameId/rematch", h.Rematch)
+	api.Post("/games/:gameId/recover", h.RecoverGame)
+	api.Post("/games/:gameId/abort", h.AbortGame)
+	api.Get("/games/:gameId/turn", h.GetTurn)
+	api.Get("/games/:gameId/players", h.GetPlayers)
 	api.Get("/games/:gameId/board", h.GetBoard)
+	api.Get("/games/:gameId/scoresheet", h.GetScoresheet)
+	api.Get("/games/:gameId/analysis", h.AnalyzeGame)
+	api.Get("/games/:gameId/hint", h.GetHint)
+	api.Post("/games/:gameId/grade", h.GradeMove)
+	api.Get("/games/:gameId/search-stream", conns.handler(always), h.SearchStream)
 
 	return app
 }
 
+// limiterStorage returns persistent counters for the named limiter, nil
+// selects the limiter's in-memory default
+func limiterStorage(store *storage.Store, name string) fiber.Storage {
+	if store == nil {
+		return nil
+	}
+	return store.RateLimiter(name)
+}
+
+// clientIP returns the address limits, budgets and sessions key on: the
+// connection address, or for a request from a trusted proxy the last
+// X-Forwarded-For entry, which that proxy appended and the client cannot set
+func clientIP(c *fiber.Ctx) string {
+	if !c.App().Config().EnableTrustedProxyCheck || !c.IsProxyTrusted() {
+		return c.IP()
+	}
+	xff := c.Get(fiber.HeaderXForwardedFor)
+	ip := strings.TrimSpace(xff[strings.LastIndex(xff, ",")+1:])
+	if net.ParseIP(ip) == nil {
+		return c.IP()
+	}
+	return ip
+}
+
+// gameIDValidator rejects game routes whose :gameId is not a UUID
+func gameIDValidator(c *fiber.Ctx) error {
+	if !isValidUUID(c.Params("gameId")) {
+		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+			Error:   "invalid game ID format",
+			Code:    core.ErrInvalidRequest,
+			Details: "game ID must be a valid UUID",
+		})
+	}
+	return c.Next()
+}
+
 // contentTypeValidator ensures POST and PUT requests have application/json
 func contentTypeValidator(c *fiber.Ctx) error {
 	method := c.Method()
@@ -190,11 +280,37 @@ func customErrorHandler(c *fiber.Ctx, err error) error {
 }
 
 // Health check endpoint with storage status
+// Returns 503 while the engine pool warms up when warmup gating is enabled
 func (h *HTTPHandler) Health(c *fiber.Ctx) error {
+	status, code := "healthy", fiber.StatusOK
+	if h.proc.Warming() {
+		status, code = "warming", fiber.StatusServiceUnavailable
+	}
+	return c.Status(code).JSON(fiber.Map{
+		"status":      status,
+		"time":        time.Now().Unix(),
+		"storage":     h.svc.GetStorageHealth(),
+		"engine":      h.proc.EngineHealth(),
+		"enginePool":  h.proc.EnginePoolHealth(),
+		"engineQueue": h.proc.EngineQueueStats(),
+		"games":       h.svc.GameCount(),
+	})
+}
+
+// Metrics reports processor counters
+func (h *HTTPHandler) Metrics(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{
-		"status":  "healthy",
-		"time":    time.Now().Unix(),
-		"storage": h.svc.GetStorageHealth(),
+		"time":      time.Now().Unix(),
+		"evalCache": h.proc.EvalCacheStats(),
+	})
+}
+
+// GetVersion returns the server, API and engine versions
+func (h *HTTPHandler) GetVersion(c *fiber.Ctx) error {
+	return c.JSON(core.VersionResponse{
+		Server: Version,
+		API:    APIVersion,
+		Engine: h.proc.EngineName(),
 	})
 }
 
@@ -220,36 +336,47 @@ func (h *HTTPHandler) CreateGame(c *fiber.Ctx) error {
 	var req core.CreateGameRequest
 	req = *(validatedBody.(*core.CreateGameRequest))
 
+	wait, err := strconv.ParseBool(c.Query("waitFirstMove", "false"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+			Error:   "invalid waitFirstMove",
+			Code:    core.ErrInvalidRequest,
+			Details: "waitFirstMove must be true or false",
+		})
+	}
+	req.WaitFirstMove = wait
+
 	// Retrieve authenticated user ID if available
 	userID, _ := c.Locals("userID").(string)
 
 	// Generate game ID via service with optional user context
 	cmd := processor.NewCreateGameCommand(req)
 	cmd.UserID = userID // Add user ID to command if authenticated
+	cmd.ClientIP = clientIP(c)
 
 	resp := h.proc.Execute(cmd)
 
 	// Return appropriate HTTP response
 	if !resp.Success {
-		return c.Status(fiber.StatusBadRequest).JSON(resp.Error)
+		statusCode := fiber.StatusBadRequest
+		switch resp.Error.Code {
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		case core.ErrEngineSaturated:
+			statusCode = fiber.StatusServiceUnavailable
+		case core.ErrResourceLimit:
+			statusCode = fiber.StatusServiceUnavailable
+		}
+		return c.Status(statusCode).JSON(resp.Error)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(resp.Data)
+	return sendGame(c, fiber.StatusCreated, resp.Data)
 }
 
 // ConfigurePlayers updates player configuration mid-game
 func (h *HTTPHandler) ConfigurePlayers(c *fiber.Ctx) error {
 	gameID := c.Params("gameId")
 
-	// Validate UUID format
-	if !isValidUUID(gameID) {
-		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
-			Error:   "invalid game ID format",
-			Code:    core.ErrInvalidRequest,
-			Details: "game ID must be a valid UUID",
-		})
-	}
-
 	// Ensure middleware validation ran
 	validated, ok := c.Locals("validated").(bool)
 	if !ok || !validated {
@@ -272,32 +399,42 @@ func (h *HTTPHandler) ConfigurePlayers(c *fiber.Ctx) error {
 
 	// Create command and execute
 	cmd := processor.NewConfigurePlayersCommand(gameID, req)
+	cmd.ClientIP = clientIP(c)
 	resp := h.proc.Execute(cmd)
 
 	// Return appropriate HTTP response
 	if !resp.Success {
 		statusCode := fiber.StatusBadRequest
-		if resp.Error.Code == core.ErrGameNotFound {
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
 			statusCode = fiber.StatusNotFound
+		case core.ErrMoveConflict:
+			statusCode = fiber.StatusConflict
 		}
 		return c.Status(statusCode).JSON(resp.Error)
 	}
 
-	return c.JSON(resp.Data)
+	return sendGame(c, fiber.StatusOK, resp.Data)
 }
 
 // GetGame retrieves current game state
 func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 	gameID := c.Params("gameId")
 
-	// Validate UUID format
-	if !isValidUUID(gameID) {
+	// Optional slice of the move history, the response carries the total count
+	from, err := queryNonNegative(c, "movesFrom")
+	limit, errLimit := queryNonNegative(c, "movesLimit")
+	if err == nil {
+		err = errLimit
+	}
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
-			Error:   "invalid game ID format",
+			Error:   "invalid move window",
 			Code:    core.ErrInvalidRequest,
-			Details: "game ID must be a valid UUID",
+			Details: err.Error(),
 		})
 	}
+	window := core.MoveWindow{From: from, Limit: limit}
 
 	// Check for long-polling parameters
 	waitStr := c.Query("wait", "false")
@@ -306,7 +443,7 @@ func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 	// Non-wait path - existing behavior
 	if waitStr != "true" {
 		// Create command and execute
-		cmd := processor.NewGetGameCommand(gameID)
+		cmd := processor.NewGetGameCommand(gameID, window)
 		resp := h.proc.Execute(cmd)
 
 		// Return appropriate HTTP response
@@ -314,7 +451,7 @@ func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(resp.Error)
 		}
 
-		return c.JSON(resp.Data)
+		return sendGame(c, fiber.StatusOK, resp.Data)
 	}
 
 	// Long-polling path
@@ -336,12 +473,12 @@ func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 
 	// If move count already different, return immediately
 	if moveCount != currentMoveCount {
-		cmd := processor.NewGetGameCommand(gameID)
+		cmd := processor.NewGetGameCommand(gameID, window)
 		resp := h.proc.Execute(cmd)
 		if !resp.Success {
 			return c.Status(fiber.StatusNotFound).JSON(resp.Error)
 		}
-		return c.JSON(resp.Data)
+		return sendGame(c, fiber.StatusOK, resp.Data)
 	}
 
 	// Register wait with service
@@ -352,7 +489,7 @@ func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 	select {
 	case <-notify:
 		// State changed or timeout, get fresh game state
-		cmd := processor.NewGetGameCommand(gameID)
+		cmd := processor.NewGetGameCommand(gameID, window)
 		resp := h.proc.Execute(cmd)
 
 		// Game might have been deleted
@@ -360,7 +497,7 @@ func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 			return c.Status(fiber.StatusNotFound).JSON(resp.Error)
 		}
 
-		return c.JSON(resp.Data)
+		return sendGame(c, fiber.StatusOK, resp.Data)
 
 	case <-ctx.Done():
 		// Client disconnected
@@ -368,18 +505,23 @@ func (h *HTTPHandler) GetGame(c *fiber.Ctx) error {
 	}
 }
 
+// queryNonNegative parses an optional integer query parameter, zero when absent
+func queryNonNegative(c *fiber.Ctx, name string) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
 // MakeMove submits a move
 func (h *HTTPHandler) MakeMove(c *fiber.Ctx) error {
 	gameID := c.Params("gameId")
 
-	if !isValidUUID(gameID) {
-		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
-			Error:   "invalid game ID format",
-			Code:    core.ErrInvalidRequest,
-			Details: "game ID must be a valid UUID",
-		})
-	}
-
 	validated, ok := c.Locals("validated").(bool)
 	if !ok || !validated {
 		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
@@ -403,6 +545,7 @@ func (h *HTTPHandler) MakeMove(c *fiber.Ctx) error {
 
 	cmd := processor.NewMakeMoveCommand(gameID, req)
 	cmd.UserID = userID // Pass user context for authorization
+	cmd.ClientIP = clientIP(c)
 
 	resp := h.proc.Execute(cmd)
 
@@ -413,26 +556,71 @@ func (h *HTTPHandler) MakeMove(c *fiber.Ctx) error {
 			statusCode = fiber.StatusNotFound
 		case core.ErrUnauthorized:
 			statusCode = fiber.StatusForbidden
+		case core.ErrMoveConflict:
+			statusCode = fiber.StatusConflict
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		case core.ErrEngineSaturated:
+			statusCode = fiber.StatusServiceUnavailable
 		}
 		return c.Status(statusCode).JSON(resp.Error)
 	}
 
-	return c.JSON(resp.Data)
+	return sendGame(c, fiber.StatusOK, resp.Data)
 }
 
-// UndoMove undoes one or more moves
-func (h *HTTPHandler) UndoMove(c *fiber.Ctx) error {
+// MakeMoves submits several human moves in one request
+func (h *HTTPHandler) MakeMoves(c *fiber.Ctx) error {
 	gameID := c.Params("gameId")
 
-	// Validate UUID format
-	if !isValidUUID(gameID) {
-		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
-			Error:   "invalid game ID format",
-			Code:    core.ErrInvalidRequest,
-			Details: "game ID must be a valid UUID",
+	validated, ok := c.Locals("validated").(bool)
+	if !ok || !validated {
+		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
+			Error: "validation bypass detected",
+			Code:  core.ErrInternalError,
 		})
 	}
 
+	validatedBody := c.Locals("validatedBody")
+	if validatedBody == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
+			Error: "validation data missing",
+			Code:  core.ErrInternalError,
+		})
+	}
+	req := *(validatedBody.(*core.BatchMoveRequest))
+
+	// Get authenticated user ID if present
+	userID, _ := c.Locals("userID").(string)
+
+	cmd := processor.NewMakeMovesCommand(gameID, req)
+	cmd.UserID = userID // Pass user context for authorization
+	cmd.ClientIP = clientIP(c)
+
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusBadRequest
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
+			statusCode = fiber.StatusNotFound
+		case core.ErrUnauthorized:
+			statusCode = fiber.StatusForbidden
+		case core.ErrMoveConflict:
+			statusCode = fiber.StatusConflict
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return sendGame(c, fiber.StatusOK, resp.Data)
+}
+
+// UndoMove undoes one or more moves
+func (h *HTTPHandler) UndoMove(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
 	// Ensure middleware validation ran
 	validated, ok := c.Locals("validated").(bool)
 	if !ok || !validated {
@@ -466,22 +654,101 @@ func (h *HTTPHandler) UndoMove(c *fiber.Ctx) error {
 		return c.Status(statusCode).JSON(resp.Error)
 	}
 
-	return c.JSON(resp.Data)
+	return sendGame(c, fiber.StatusOK, resp.Data)
 }
 
-// DeleteGame ends and cleans up a game
-func (h *HTTPHandler) DeleteGame(c *fiber.Ctx) error {
+// ResetGame clears all moves and returns the game to its starting position
+func (h *HTTPHandler) ResetGame(c *fiber.Ctx) error {
 	gameID := c.Params("gameId")
 
-	// Validate UUID format
-	if !isValidUUID(gameID) {
-		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
-			Error:   "invalid game ID format",
-			Code:    core.ErrInvalidRequest,
-			Details: "game ID must be a valid UUID",
-		})
+	// Create command and execute
+	cmd := processor.NewResetGameCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	// Return appropriate HTTP response
+	if !resp.Success {
+		statusCode := fiber.StatusBadRequest
+		if resp.Error.Code == core.ErrGameNotFound {
+			statusCode = fiber.StatusNotFound
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return sendGame(c, fiber.StatusOK, resp.Data)
+}
+
+// RecoverGame returns a game stuck on an engine error to play
+func (h *HTTPHandler) RecoverGame(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	cmd := processor.NewRecoverGameCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusBadRequest
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
+			statusCode = fiber.StatusNotFound
+		case core.ErrResourceLimit:
+			statusCode = fiber.StatusServiceUnavailable
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return sendGame(c, fiber.StatusOK, resp.Data)
+}
+
+// AbortGame ends an unfinished game without a result
+func (h *HTTPHandler) AbortGame(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	cmd := processor.NewAbortGameCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusBadRequest
+		if resp.Error.Code == core.ErrGameNotFound {
+			statusCode = fiber.StatusNotFound
+		}
+		return c.Status(statusCode).JSON(resp.Error)
 	}
 
+	return sendGame(c, fiber.StatusOK, resp.Data)
+}
+
+// Rematch creates a new game from a finished one with colors swapped
+func (h *HTTPHandler) Rematch(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	// Create command and execute
+	cmd := processor.NewRematchCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	// Return appropriate HTTP response
+	if !resp.Success {
+		statusCode := fiber.StatusBadRequest
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
+			statusCode = fiber.StatusNotFound
+		case core.ErrResourceLimit:
+			statusCode = fiber.StatusServiceUnavailable
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		case core.ErrEngineSaturated:
+			statusCode = fiber.StatusServiceUnavailable
+		case core.ErrInternalError:
+			statusCode = fiber.StatusInternalServerError
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return sendGame(c, fiber.StatusCreated, resp.Data)
+}
+
+// DeleteGame ends and cleans up a game
+func (h *HTTPHandler) DeleteGame(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
 	// Create command and execute
 	cmd := processor.NewDeleteGameCommand(gameID)
 	resp := h.proc.Execute(cmd)
@@ -494,21 +761,75 @@ func (h *HTTPHandler) DeleteGame(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// GetPlayers returns the full player configuration of a game
+func (h *HTTPHandler) GetPlayers(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	// Create command and execute
+	cmd := processor.NewGetPlayersCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	// Return appropriate HTTP response
+	if !resp.Success {
+		return c.Status(fiber.StatusNotFound).JSON(resp.Error)
+	}
+
+	return c.JSON(resp.Data)
+}
+
+// GetTurn returns whose turn it is, for clients polling at high frequency
+func (h *HTTPHandler) GetTurn(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	// Create command and execute
+	cmd := processor.NewGetTurnCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	// Return appropriate HTTP response
+	if !resp.Success {
+		return c.Status(fiber.StatusNotFound).JSON(resp.Error)
+	}
+
+	return c.JSON(resp.Data)
+}
+
 // GetBoard returns ASCII representation of the board
 func (h *HTTPHandler) GetBoard(c *fiber.Ctx) error {
 	gameID := c.Params("gameId")
 
-	// Validate UUID format
-	if !isValidUUID(gameID) {
+	coords, err := strconv.ParseBool(c.Query("coords", "true"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
-			Error:   "invalid game ID format",
+			Error:   "invalid coords",
 			Code:    core.ErrInvalidRequest,
-			Details: "game ID must be a valid UUID",
+			Details: "coords must be true or false",
+		})
+	}
+
+	// Piece letters as the empty glyph would make the board ambiguous
+	opts := board.ASCIIOptions{NoCoords: !coords}
+	if empty := c.Query("empty"); empty != "" {
+		if len(empty) != 1 || empty[0] < ' ' || empty[0] > '~' || strings.ContainsRune("KQRBNPkqrbnp", rune(empty[0])) {
+			return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+				Error:   "invalid empty",
+				Code:    core.ErrInvalidRequest,
+				Details: "empty must be a single printable ASCII character other than a piece letter",
+			})
+		}
+		opts.Empty = empty[0]
+	}
+
+	status, err := strconv.ParseBool(c.Query("status", "false"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+			Error:   "invalid status",
+			Code:    core.ErrInvalidRequest,
+			Details: "status must be true or false",
 		})
 	}
 
 	// Create command and execute
-	cmd := processor.NewGetBoardCommand(gameID)
+	cmd := processor.NewGetBoardCommand(gameID, opts, status)
 	resp := h.proc.Execute(cmd)
 
 	// Return appropriate HTTP response
@@ -519,3 +840,190 @@ func (h *HTTPHandler) GetBoard(c *fiber.Ctx) error {
 	return c.JSON(resp.Data)
 }
 
+// GetScoresheet returns the move history as numbered SAN pairs
+func (h *HTTPHandler) GetScoresheet(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	cmd := processor.NewGetScoresheetCommand(gameID)
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusInternalServerError
+		if resp.Error.Code == core.ErrGameNotFound {
+			statusCode = fiber.StatusNotFound
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return c.JSON(resp.Data)
+}
+
+// AnalyzeGame returns a fixed-depth engine evaluation of every move
+func (h *HTTPHandler) AnalyzeGame(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	depth, err := strconv.Atoi(c.Query("depth", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+			Error:   "invalid depth",
+			Code:    core.ErrInvalidRequest,
+			Details: "depth must be an integer",
+		})
+	}
+
+	cmd := processor.NewAnalyzeGameCommand(gameID, depth)
+	cmd.ClientIP = clientIP(c)
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusInternalServerError
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
+			statusCode = fiber.StatusNotFound
+		case core.ErrInvalidRequest, core.ErrInvalidFEN:
+			statusCode = fiber.StatusBadRequest
+		case core.ErrResourceLimit:
+			statusCode = fiber.StatusServiceUnavailable
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return c.JSON(resp.Data)
+}
+
+// GetHint suggests a move for the side to move
+func (h *HTTPHandler) GetHint(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	strength, err := strconv.Atoi(c.Query("strength", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(core.ErrorResponse{
+			Error:   "invalid strength",
+			Code:    core.ErrInvalidRequest,
+			Details: "strength must be an integer",
+		})
+	}
+
+	cmd := processor.NewGetHintCommand(gameID, strength)
+	cmd.ClientIP = clientIP(c)
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusInternalServerError
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
+			statusCode = fiber.StatusNotFound
+		case core.ErrInvalidRequest, core.ErrGameOver:
+			statusCode = fiber.StatusBadRequest
+		case core.ErrResourceLimit:
+			statusCode = fiber.StatusServiceUnavailable
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return c.JSON(resp.Data)
+}
+
+// GradeMove compares a candidate move with the engine's best move without playing it
+func (h *HTTPHandler) GradeMove(c *fiber.Ctx) error {
+	gameID := c.Params("gameId")
+
+	validated, ok := c.Locals("validated").(bool)
+	if !ok || !validated {
+		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
+			Error: "validation bypass detected",
+			Code:  core.ErrInternalError,
+		})
+	}
+
+	validatedBody := c.Locals("validatedBody")
+	if validatedBody == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(core.ErrorResponse{
+			Error: "validation data missing",
+			Code:  core.ErrInternalError,
+		})
+	}
+	req := *(validatedBody.(*core.GradeRequest))
+
+	cmd := processor.NewGradeMoveCommand(gameID, req)
+	cmd.ClientIP = clientIP(c)
+	resp := h.proc.Execute(cmd)
+
+	if !resp.Success {
+		statusCode := fiber.StatusInternalServerError
+		switch resp.Error.Code {
+		case core.ErrGameNotFound:
+			statusCode = fiber.StatusNotFound
+		case core.ErrInvalidRequest, core.ErrInvalidMove, core.ErrInvalidFEN, core.ErrGameOver:
+			statusCode = fiber.StatusBadRequest
+		case core.ErrResourceLimit:
+			statusCode = fiber.StatusServiceUnavailable
+		case core.ErrRateLimitExceeded:
+			statusCode = fiber.StatusTooManyRequests
+		}
+		return c.Status(statusCode).JSON(resp.Error)
+	}
+
+	return c.JSON(resp.Data)
+}
+
+// SearchStream sends the depth and score of the running computer move search
+// as server-sent events, ending with the engine's best move
+func (h *HTTPHandler) SearchStream(c *fiber.Ctx) error {
+	sub, errResp := h.proc.SubscribeSearch(c.Params("gameId"))
+	if errResp != nil {
+		statusCode := fiber.StatusBadRequest
+		if errResp.Code == core.ErrGameNotFound {
+			statusCode = fiber.StatusNotFound
+		}
+		return c.Status(statusCode).JSON(errResp)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	release := streamRelease(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer release()
+		defer sub.Close()
+		for {
+			select {
+			case info := <-sub.Info:
+				if writeEvent(w, "info", info) != nil {
+					return // Client gone
+				}
+			case <-sub.Done:
+				// Progress that arrived just before the best move is still sent first
+				for len(sub.Info) > 0 {
+					writeEvent(w, "info", <-sub.Info)
+				}
+				move, err := sub.Result()
+				if err != nil {
+					writeEvent(w, "error", core.ErrorResponse{
+						Error:   "engine search failed",
+						Code:    core.ErrInternalError,
+						Details: err.Error(),
+					})
+					return
+				}
+				writeEvent(w, "bestmove", core.SearchEnd{Move: move})
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeEvent writes one server-sent event with a JSON payload and flushes it
+func writeEvent(w *bufio.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return w.Flush()
+}
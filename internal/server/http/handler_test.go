@@ -0,0 +1,60 @@
+package http
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestIP returns the client IP the engine budget and limits would key on
+func requestIP(t *testing.T, app *fiber.App, forwardedFor string) string {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/", nil)
+	if forwardedFor != "" {
+		req.Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func newIPApp(config fiber.Config) *fiber.App {
+	app := fiber.New(config)
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(clientIP(c))
+	})
+	return app
+}
+
+func TestClientIPIgnoresForwardedForWithoutTrustedProxy(t *testing.T) {
+	app := newIPApp(fiber.Config{})
+
+	direct := requestIP(t, app, "")
+	for _, spoofed := range []string{"1.1.1.1", "2.2.2.2, 3.3.3.3"} {
+		if got := requestIP(t, app, spoofed); got != direct {
+			t.Fatalf("X-Forwarded-For %q keyed as %q, want connection address %q", spoofed, got, direct)
+		}
+	}
+}
+
+func TestClientIPUsesProxyAppendedAddress(t *testing.T) {
+	// app.Test connects from 0.0.0.0
+	app := newIPApp(fiber.Config{EnableTrustedProxyCheck: true, TrustedProxies: []string{"0.0.0.0"}})
+
+	if got := requestIP(t, app, "1.1.1.1, 2.2.2.2"); got != "2.2.2.2" {
+		t.Fatalf("clientIP = %q, want the proxy-appended 2.2.2.2", got)
+	}
+	if got := requestIP(t, app, "not-an-ip"); got != requestIP(t, app, "") {
+		t.Fatalf("invalid X-Forwarded-For keyed as %q, want the connection address", got)
+	}
+
+	untrusted := newIPApp(fiber.Config{EnableTrustedProxyCheck: true, TrustedProxies: []string{"10.0.0.1"}})
+	if got := requestIP(t, untrusted, "1.1.1.1"); got == "1.1.1.1" {
+		t.Fatal("X-Forwarded-For believed from a proxy that is not trusted")
+	}
+}
@@ -13,7 +13,36 @@ import (
 )
 
 // Add validator instance near top of file
-var validate = validator.New()
+var validate = newValidator()
+
+// newValidator reports fields by their JSON names so error paths match the request body
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	// Case and surrounding space are normalized by the processor
+	v.RegisterValidation("uci", func(fl validator.FieldLevel) bool {
+		return core.IsUCIMove(strings.ToLower(strings.TrimSpace(fl.Field().String())))
+	})
+	return v
+}
+
+// fieldPath returns the dotted JSON path of a failed field without the request type
+// e.g. "CreateGameRequest.white.searchTime" becomes "white.searchTime"
+func fieldPath(err validator.FieldError) string {
+	if _, path, ok := strings.Cut(err.Namespace(), "."); ok {
+		return path
+	}
+	return err.Field()
+}
 
 // Add custom validation middleware function
 func validationMiddleware(c *fiber.Ctx) error {
@@ -32,10 +61,14 @@ func validationMiddleware(c *fiber.Ctx) error {
 		requestType = &core.CreateGameRequest{}
 	case strings.HasSuffix(path, "/players") && method == fiber.MethodPut:
 		requestType = &core.ConfigurePlayersRequest{}
+	case strings.HasSuffix(path, "/moves/batch") && method == fiber.MethodPost:
+		requestType = &core.BatchMoveRequest{}
 	case strings.HasSuffix(path, "/moves") && method == fiber.MethodPost:
 		requestType = &core.MoveRequest{}
 	case strings.HasSuffix(path, "/undo") && method == fiber.MethodPost:
 		requestType = &core.UndoRequest{}
+	case strings.HasSuffix(path, "/grade") && method == fiber.MethodPost:
+		requestType = &core.GradeRequest{}
 	default:
 		return c.Next() // No validation for unknown endpoints
 	}
@@ -56,29 +89,32 @@ func validationMiddleware(c *fiber.Ctx) error {
 			if details.Len() > 0 {
 				details.WriteString("; ")
 			}
+			field := fieldPath(err)
 			switch err.Tag() {
 			case "required":
-				details.WriteString(fmt.Sprintf("%s is required", err.Field()))
+				details.WriteString(fmt.Sprintf("%s is required", field))
 			case "oneof":
-				details.WriteString(fmt.Sprintf("%s must be one of [%s]", err.Field(), err.Param()))
+				details.WriteString(fmt.Sprintf("%s must be one of [%s]", field, err.Param()))
 			case "min":
 				if err.Type().Kind() == reflect.String {
-					details.WriteString(fmt.Sprintf("%s must be at least %s characters", err.Field(), err.Param()))
+					details.WriteString(fmt.Sprintf("%s must be at least %s characters", field, err.Param()))
 				} else {
-					details.WriteString(fmt.Sprintf("%s must be at least %s", err.Field(), err.Param()))
+					details.WriteString(fmt.Sprintf("%s must be at least %s", field, err.Param()))
 				}
 			case "max":
 				if err.Type().Kind() == reflect.String {
-					details.WriteString(fmt.Sprintf("%s must be at most %s characters", err.Field(), err.Param()))
+					details.WriteString(fmt.Sprintf("%s must be at most %s characters", field, err.Param()))
 				} else {
-					details.WriteString(fmt.Sprintf("%s must be at most %s", err.Field(), err.Param()))
+					details.WriteString(fmt.Sprintf("%s must be at most %s", field, err.Param()))
 				}
+			case "uci":
+				details.WriteString(fmt.Sprintf("%s is not valid UCI", field))
+			case "uci|eq=cccc":
+				details.WriteString(fmt.Sprintf("%s must be a UCI move or cccc", field))
 			case "omitempty": // Skip, a control tag that doesn't error
 				continue
-			case "dive": // Skip, panics on wrong type, no error handling since current code does not call validator on slice or map
-				continue
 			default:
-				details.WriteString(fmt.Sprintf("%s failed %s validation", err.Field(), err.Tag()))
+				details.WriteString(fmt.Sprintf("%s failed %s validation", field, err.Tag()))
 			}
 		}
 
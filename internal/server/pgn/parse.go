@@ -0,0 +1,218 @@
+package pgn
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"chess/internal/server/board"
+)
+
+var (
+	tagPattern        = regexp.MustCompile(`^\[(\w+)\s+"((?:[^"\\]|\\.)*)"\]$`)
+	moveNumberPattern = regexp.MustCompile(`^\d+\.+`)
+)
+
+// ReplayError reports the ply at which a parsed game stopped replaying
+type ReplayError struct {
+	Ply  int    // 1-based half-move index
+	Move string // SAN as written in the PGN
+	Err  error
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("ply %d (%s): %v", e.Ply, e.Move, e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads all games from PGN text, converting SAN moves to UCI
+// If a game contains an illegal move, the games parsed so far are returned
+// with a ReplayError; the failing game holds the moves before the error
+func Parse(text string) ([]*Game, error) {
+	var games []*Game
+	for _, chunk := range splitGames(text) {
+		g, err := parseGame(chunk)
+		if g != nil {
+			games = append(games, g)
+		}
+		if err != nil {
+			return games, err
+		}
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("no games found")
+	}
+	return games, nil
+}
+
+// splitGames separates games on the tag section that follows movetext
+func splitGames(text string) []string {
+	var (
+		games       []string
+		current     strings.Builder
+		inMovetext  bool
+		hasContents bool
+	)
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && inMovetext {
+			games = append(games, current.String())
+			current.Reset()
+			inMovetext = false
+		}
+		if trimmed != "" && !strings.HasPrefix(trimmed, "[") && !strings.HasPrefix(trimmed, "%") {
+			inMovetext = true
+		}
+		if trimmed != "" {
+			hasContents = true
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	if hasContents && strings.TrimSpace(current.String()) != "" {
+		games = append(games, current.String())
+	}
+	return games
+}
+
+func parseGame(text string) (*Game, error) {
+	g := &Game{Result: "*"}
+	var movetext strings.Builder
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		// Escape mechanism lines are ignored
+		if strings.HasPrefix(trimmed, "%") {
+			continue
+		}
+		if m := tagPattern.FindStringSubmatch(trimmed); m != nil {
+			g.setTag(m[1], unescape(m[2]))
+			continue
+		}
+		movetext.WriteString(line)
+		movetext.WriteByte('\n')
+	}
+
+	sans, result := tokenizeMovetext(movetext.String())
+	if result != "" {
+		g.Result = result
+	}
+
+	initialFEN := g.InitialFEN
+	if initialFEN == "" {
+		initialFEN = board.StartingFEN
+	}
+	b, err := board.ParseFEN(initialFEN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FEN tag: %w", err)
+	}
+
+	for i, san := range sans {
+		uci, err := b.ParseSAN(san)
+		if err == nil {
+			b, err = b.ApplyMove(uci)
+		}
+		if err != nil {
+			return g, &ReplayError{Ply: i + 1, Move: san, Err: err}
+		}
+		g.Moves = append(g.Moves, uci)
+	}
+
+	return g, nil
+}
+
+// setTag stores roster and setup tags in their fields, others in Extra
+func (g *Game) setTag(name, value string) {
+	switch name {
+	case "Event":
+		g.Event = value
+	case "Site":
+		g.Site = value
+	case "Date":
+		if t, err := time.Parse("2006.01.02", value); err == nil {
+			g.Date = t
+		}
+	case "Round":
+		g.Round = value
+	case "White":
+		g.White = value
+	case "Black":
+		g.Black = value
+	case "Result":
+		g.Result = value
+	case "FEN":
+		g.InitialFEN = value
+	case "SetUp":
+		// Implied by the FEN tag
+	default:
+		g.Extra = append(g.Extra, Tag{Name: name, Value: value})
+	}
+}
+
+// tokenizeMovetext extracts SAN moves and the game termination marker,
+// skipping comments, variations, NAGs and move numbers
+func tokenizeMovetext(text string) ([]string, string) {
+	var (
+		sans   []string
+		result string
+		token  strings.Builder
+		depth  int // variation nesting
+	)
+
+	flush := func() {
+		tok := token.String()
+		token.Reset()
+		tok = moveNumberPattern.ReplaceAllString(tok, "")
+		switch {
+		case tok == "" || depth > 0 || strings.HasPrefix(tok, "$"):
+		case tok == "1-0" || tok == "0-1" || tok == "1/2-1/2" || tok == "*":
+			result = tok
+		default:
+			sans = append(sans, tok)
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '{':
+			flush()
+			if end := strings.IndexByte(text[i:], '}'); end >= 0 {
+				i += end
+			} else {
+				i = len(text)
+			}
+		case c == ';':
+			flush()
+			if end := strings.IndexByte(text[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(text)
+			}
+		case c == '(':
+			flush()
+			depth++
+		case c == ')':
+			flush()
+			if depth > 0 {
+				depth--
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		default:
+			token.WriteByte(c)
+		}
+	}
+	flush()
+
+	return sans, result
+}
+
+func unescape(s string) string {
+	s = strings.ReplaceAll(s, `\"`, `"`)
+	return strings.ReplaceAll(s, `\\`, `\`)
+}
@@ -0,0 +1,232 @@
+package pgn
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"chess/internal/server/board"
+	"chess/internal/server/core"
+)
+
+const (
+	// maxLineLength is the PGN export format movetext line limit
+	maxLineLength = 79
+)
+
+// Tag is a PGN tag pair
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Game holds the data needed to write a single PGN game
+type Game struct {
+	Event      string
+	Site       string
+	Date       time.Time
+	Round      string
+	White      string
+	Black      string
+	Result     string   // "1-0", "0-1", "1/2-1/2" or "*"
+	InitialFEN string   // empty or StartingFEN for the standard position
+	Moves      []string // UCI notation
+	SAN        []string // Moves in SAN when already known, otherwise replayed from Moves
+	Extra      []Tag    // additional tags written after the roster
+}
+
+// Termination returns the PGN Termination tag value for a game end reason,
+// empty for a game without one
+func Termination(reason string) string {
+	switch reason {
+	case "":
+		return ""
+	case core.ReasonAborted:
+		return "abandoned"
+	case core.ReasonTimeout:
+		return "time forfeit"
+	case core.ReasonDeadPosition:
+		return "adjudication"
+	default:
+		// Checkmate, resignation and agreement all end a game normally
+		return "normal"
+	}
+}
+
+// Ply is a replayed half-move with its notations and the resulting position
+type Ply struct {
+	UCI string
+	SAN string
+	FEN string // Position after the move
+}
+
+// Replay applies UCI moves from the initial position
+// On an illegal move the plies replayed so far are returned with the error
+func Replay(initialFEN string, moves []string) ([]Ply, error) {
+	if initialFEN == "" {
+		initialFEN = board.StartingFEN
+	}
+	b, err := board.ParseFEN(initialFEN)
+	if err != nil {
+		return nil, err
+	}
+
+	plies := make([]Ply, 0, len(moves))
+	for i, uci := range moves {
+		san, err := b.ToSAN(uci)
+		if err != nil {
+			return plies, fmt.Errorf("move %d: %w", i+1, err)
+		}
+		b, err = b.ApplyMove(uci)
+		if err != nil {
+			return plies, fmt.Errorf("move %d: %w", i+1, err)
+		}
+		plies = append(plies, Ply{UCI: uci, SAN: san, FEN: b.ToFEN()})
+	}
+	return plies, nil
+}
+
+// MovesToSAN replays UCI moves from the initial position and returns them in SAN
+func MovesToSAN(initialFEN string, moves []string) ([]string, error) {
+	plies, err := Replay(initialFEN, moves)
+	if err != nil {
+		return nil, err
+	}
+
+	sans := make([]string, len(plies))
+	for i, p := range plies {
+		sans[i] = p.SAN
+	}
+	return sans, nil
+}
+
+// MovePair is one numbered line of a scoresheet
+// White is empty on the first line of a game where Black moves first,
+// Black is empty on the last line when White made the final move
+type MovePair struct {
+	Number int
+	White  string
+	Black  string
+}
+
+// Pair groups moves into numbered white/black lines, numbering from the
+// fullmove counter and side to move of the initial position
+func Pair(initialFEN string, moves []string) []MovePair {
+	moveNumber, blackToMove := 1, false
+	if initialFEN != "" {
+		if fields := strings.Fields(initialFEN); len(fields) == 6 {
+			blackToMove = fields[1] == "b"
+			fmt.Sscanf(fields[5], "%d", &moveNumber)
+		}
+	}
+
+	pairs := make([]MovePair, 0, (len(moves)+2)/2)
+	for _, move := range moves {
+		if blackToMove {
+			if len(pairs) == 0 {
+				pairs = append(pairs, MovePair{Number: moveNumber})
+			}
+			pairs[len(pairs)-1].Black = move
+			moveNumber++
+		} else {
+			pairs = append(pairs, MovePair{Number: moveNumber, White: move})
+		}
+		blackToMove = !blackToMove
+	}
+	return pairs
+}
+
+// Write writes a single game in PGN export format followed by a blank line
+func Write(w io.Writer, g *Game) error {
+	sans := g.SAN
+	if len(sans) != len(g.Moves) {
+		var err error
+		if sans, err = MovesToSAN(g.InitialFEN, g.Moves); err != nil {
+			return err
+		}
+	}
+
+	result := g.Result
+	if result == "" {
+		result = "*"
+	}
+
+	// Seven Tag Roster
+	tags := []Tag{
+		{"Event", orUnknown(g.Event)},
+		{"Site", orUnknown(g.Site)},
+		{"Date", formatDate(g.Date)},
+		{"Round", orUnknown(g.Round)},
+		{"White", orUnknown(g.White)},
+		{"Black", orUnknown(g.Black)},
+		{"Result", result},
+	}
+	if g.InitialFEN != "" && g.InitialFEN != board.StartingFEN {
+		tags = append(tags, Tag{"SetUp", "1"}, Tag{"FEN", g.InitialFEN})
+	}
+	tags = append(tags, g.Extra...)
+
+	var sb strings.Builder
+	for _, t := range tags {
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", t.Name, escape(t.Value))
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(movetext(g.InitialFEN, sans, result))
+	sb.WriteString("\n\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// movetext formats SAN moves with move numbers, wrapped to the line limit
+func movetext(initialFEN string, sans []string, result string) string {
+	var tokens []string
+	for _, pair := range Pair(initialFEN, sans) {
+		if pair.White != "" {
+			tokens = append(tokens, fmt.Sprintf("%d.", pair.Number), pair.White)
+		} else {
+			tokens = append(tokens, fmt.Sprintf("%d...", pair.Number))
+		}
+		if pair.Black != "" {
+			tokens = append(tokens, pair.Black)
+		}
+	}
+	tokens = append(tokens, result)
+
+	var sb strings.Builder
+	lineLen := 0
+	for _, tok := range tokens {
+		if lineLen > 0 && lineLen+1+len(tok) > maxLineLength {
+			sb.WriteByte('\n')
+			lineLen = 0
+		}
+		if lineLen > 0 {
+			sb.WriteByte(' ')
+			lineLen++
+		}
+		sb.WriteString(tok)
+		lineLen += len(tok)
+	}
+	return sb.String()
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "????.??.??"
+	}
+	return t.UTC().Format("2006.01.02")
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "?"
+	}
+	return s
+}
+
+// escape quotes backslashes and double quotes in tag values
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
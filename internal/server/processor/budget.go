@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultEngineBudgetWindow is the rolling window engine time is accounted over
+const DefaultEngineBudgetWindow = 10 * time.Minute
+
+// engineBudget limits the engine time each game and client address may
+// consume within a rolling window
+// Checks and charges are separate, concurrent requests can overshoot by one search
+type engineBudget struct {
+	limit     time.Duration
+	window    time.Duration
+	usage     map[string][]engineUsage
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// engineUsage is one charge of engine time
+type engineUsage struct {
+	at    time.Time
+	spent time.Duration
+}
+
+func newEngineBudget(limit, window time.Duration) *engineBudget {
+	return &engineBudget{
+		limit:     limit,
+		window:    window,
+		usage:     make(map[string][]engineUsage),
+		lastSweep: time.Now(),
+	}
+}
+
+// budgetKeys names the accounts a request is charged to, empty parts are skipped
+func budgetKeys(gameID, clientIP string) []string {
+	keys := make([]string, 0, 2)
+	if gameID != "" {
+		keys = append(keys, "game:"+gameID)
+	}
+	if clientIP != "" {
+		keys = append(keys, "ip:"+clientIP)
+	}
+	return keys
+}
+
+// allow reports whether every key has room for estimate more engine time
+func (b *engineBudget) allow(estimate time.Duration, keys ...string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		if b.used(key, now)+estimate > b.limit {
+			return false
+		}
+	}
+	return true
+}
+
+// charge records spent engine time against every key
+func (b *engineBudget) charge(spent time.Duration, keys ...string) {
+	if spent <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for _, key := range keys {
+		b.usage[key] = append(b.usage[key], engineUsage{at: now, spent: spent})
+	}
+
+	// Drop accounts of finished games and departed clients once per window
+	if now.Sub(b.lastSweep) >= b.window {
+		for key := range b.usage {
+			b.used(key, now)
+		}
+		b.lastSweep = now
+	}
+}
+
+// used sums the engine time of key within the window, pruning older charges
+// Caller must hold mu
+func (b *engineBudget) used(key string, now time.Time) time.Duration {
+	entries := b.usage[key]
+	cutoff := now.Add(-b.window)
+	i := 0
+	for i < len(entries) && !entries[i].at.After(cutoff) {
+		i++
+	}
+	if i == len(entries) {
+		delete(b.usage, key)
+		return 0
+	}
+	entries = entries[i:]
+	b.usage[key] = entries
+
+	var total time.Duration
+	for _, e := range entries {
+		total += e.spent
+	}
+	return total
+}
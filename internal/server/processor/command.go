@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"chess/internal/server/board"
 	"chess/internal/server/core"
 )
 
@@ -10,19 +11,31 @@ type CommandType int
 const (
 	CmdCreateGame CommandType = iota
 	CmdConfigurePlayers
+	CmdGetPlayers
 	CmdGetGame
+	CmdGetTurn
 	CmdDeleteGame
 	CmdMakeMove
+	CmdMakeMoves
 	CmdUndoMove
+	CmdResetGame
+	CmdRematch
+	CmdRecoverGame
+	CmdAbortGame
 	CmdGetBoard
+	CmdGetScoresheet
+	CmdAnalyzeGame
+	CmdGetHint
+	CmdGradeMove
 )
 
 // Command is a unified structure for all processor operations
 type Command struct {
-	Type   CommandType
-	UserID string
-	GameID string // For game-specific commands
-	Args   any    // Command-specific arguments
+	Type     CommandType
+	UserID   string
+	GameID   string // For game-specific commands
+	ClientIP string // Requesting client, for engine budget accounting
+	Args     any    // Command-specific arguments
 }
 
 // ProcessorResponse wraps the response with metadata
@@ -48,10 +61,28 @@ func NewConfigurePlayersCommand(gameID string, req core.ConfigurePlayersRequest)
 	}
 }
 
-func NewGetGameCommand(gameID string) Command {
+// NewGetPlayersCommand reads the full player configuration of a game
+func NewGetPlayersCommand(gameID string) Command {
+	return Command{
+		Type:   CmdGetPlayers,
+		GameID: gameID,
+	}
+}
+
+// NewGetGameCommand requests the game with the moves inside window
+func NewGetGameCommand(gameID string, window core.MoveWindow) Command {
 	return Command{
 		Type:   CmdGetGame,
 		GameID: gameID,
+		Args:   window,
+	}
+}
+
+// NewGetTurnCommand reads whose turn it is without building the full game response
+func NewGetTurnCommand(gameID string) Command {
+	return Command{
+		Type:   CmdGetTurn,
+		GameID: gameID,
 	}
 }
 
@@ -63,6 +94,14 @@ func NewMakeMoveCommand(gameID string, req core.MoveRequest) Command {
 	}
 }
 
+func NewMakeMovesCommand(gameID string, req core.BatchMoveRequest) Command {
+	return Command{
+		Type:   CmdMakeMoves,
+		GameID: gameID,
+		Args:   req,
+	}
+}
+
 func NewUndoMoveCommand(gameID string, req core.UndoRequest) Command {
 	return Command{
 		Type:   CmdUndoMove,
@@ -71,6 +110,38 @@ func NewUndoMoveCommand(gameID string, req core.UndoRequest) Command {
 	}
 }
 
+// NewResetGameCommand clears all moves and restores the starting position
+func NewResetGameCommand(gameID string) Command {
+	return Command{
+		Type:   CmdResetGame,
+		GameID: gameID,
+	}
+}
+
+// NewRematchCommand creates a game from a finished one with colors swapped
+func NewRematchCommand(gameID string) Command {
+	return Command{
+		Type:   CmdRematch,
+		GameID: gameID,
+	}
+}
+
+// NewRecoverGameCommand returns a game stuck on an engine error to play
+func NewRecoverGameCommand(gameID string) Command {
+	return Command{
+		Type:   CmdRecoverGame,
+		GameID: gameID,
+	}
+}
+
+// NewAbortGameCommand ends an unfinished game without a result, keeping its record
+func NewAbortGameCommand(gameID string) Command {
+	return Command{
+		Type:   CmdAbortGame,
+		GameID: gameID,
+	}
+}
+
 func NewDeleteGameCommand(gameID string) Command {
 	return Command{
 		Type:   CmdDeleteGame,
@@ -78,9 +149,54 @@ func NewDeleteGameCommand(gameID string) Command {
 	}
 }
 
-func NewGetBoardCommand(gameID string) Command {
+// boardArgs carries the rendering options of a board request and whether to
+// add the check and game over status
+type boardArgs struct {
+	opts   board.ASCIIOptions
+	status bool
+}
+
+// NewGetBoardCommand requests the ASCII board rendered with the given options,
+// status adds whether the side to move is in check and whether the game is over
+func NewGetBoardCommand(gameID string, opts board.ASCIIOptions, status bool) Command {
 	return Command{
 		Type:   CmdGetBoard,
 		GameID: gameID,
+		Args:   boardArgs{opts: opts, status: status},
+	}
+}
+
+// NewGetScoresheetCommand requests the move history as numbered SAN pairs
+func NewGetScoresheetCommand(gameID string) Command {
+	return Command{
+		Type:   CmdGetScoresheet,
+		GameID: gameID,
+	}
+}
+
+// NewAnalyzeGameCommand requests a fixed-depth evaluation of every move, depth 0 uses the default
+func NewAnalyzeGameCommand(gameID string, depth int) Command {
+	return Command{
+		Type:   CmdAnalyzeGame,
+		GameID: gameID,
+		Args:   depth,
+	}
+}
+
+// NewGradeMoveCommand requests a comparison of a candidate move with the engine's best move
+func NewGradeMoveCommand(gameID string, req core.GradeRequest) Command {
+	return Command{
+		Type:   CmdGradeMove,
+		GameID: gameID,
+		Args:   req,
+	}
+}
+
+// NewGetHintCommand requests a move suggestion at a skill level (0-20)
+func NewGetHintCommand(gameID string, strength int) Command {
+	return Command{
+		Type:   CmdGetHint,
+		GameID: gameID,
+		Args:   strength,
 	}
 }
\ No newline at end of file
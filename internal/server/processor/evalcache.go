@@ -0,0 +1,152 @@
+package processor
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"chess/internal/server/engine"
+)
+
+// DefaultEvalCacheSize is the number of engine evaluations kept for reuse
+const DefaultEvalCacheSize = 4096
+
+// EvalCacheStats reports evaluation cache usage for /metrics
+type EvalCacheStats struct {
+	Enabled bool    `json:"enabled"`
+	Size    int     `json:"size"`
+	Entries int     `json:"entries"`
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// evalCache keeps engine search results by position and search limit
+// Positions never change, so entries leave only by LRU eviction or TTL
+type evalCache struct {
+	size   int
+	ttl    time.Duration // Zero keeps entries until evicted
+	order  *list.List    // Front is most recently used
+	items  map[string]*list.Element
+	hits   uint64
+	misses uint64
+	mu     sync.Mutex
+}
+
+type evalEntry struct {
+	key    string
+	result engine.SearchResult
+	stored time.Time
+}
+
+func newEvalCache(size int, ttl time.Duration) *evalCache {
+	return &evalCache{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// timeKey identifies a timed search of fen, skill only matters for hints
+func timeKey(fen string, timeMs, skill int) string {
+	return fmt.Sprintf("%s|t%d|s%d", fen, timeMs, skill)
+}
+
+// depthKey identifies a fixed depth search of fen
+func depthKey(fen string, depth int) string {
+	return fmt.Sprintf("%s|d%d", fen, depth)
+}
+
+// get returns a copy of the cached result for key
+func (c *evalCache) get(key string) (*engine.SearchResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if ok && c.ttl > 0 && time.Since(elem.Value.(*evalEntry).stored) > c.ttl {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(elem)
+	result := elem.Value.(*evalEntry).result
+	return &result, true
+}
+
+// put stores a copy of result, evicting the least recently used entry when full
+func (c *evalCache) put(key string, result *engine.SearchResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*evalEntry)
+		entry.result, entry.stored = *result, time.Now()
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&evalEntry{key: key, result: *result, stored: time.Now()})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*evalEntry).key)
+	}
+}
+
+func (c *evalCache) stats() EvalCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := EvalCacheStats{
+		Enabled: true,
+		Size:    c.size,
+		Entries: c.order.Len(),
+		Hits:    c.hits,
+		Misses:  c.misses,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// SetEvalCache keeps up to size engine evaluations for reuse by game end
+// checks, analysis and hints, a positive ttl expires entries, zero size disables
+func (p *Processor) SetEvalCache(size int, ttl time.Duration) {
+	if size <= 0 {
+		p.evalCache = nil
+		return
+	}
+	p.evalCache = newEvalCache(size, ttl)
+}
+
+// EvalCacheStats returns evaluation cache usage
+func (p *Processor) EvalCacheStats() EvalCacheStats {
+	if p.evalCache == nil {
+		return EvalCacheStats{}
+	}
+	return p.evalCache.stats()
+}
+
+// cachedEval returns the cached result for key, always a miss without a cache
+func (p *Processor) cachedEval(key string) (*engine.SearchResult, bool) {
+	if p.evalCache == nil {
+		return nil, false
+	}
+	return p.evalCache.get(key)
+}
+
+// storeEval caches result under key when the cache is enabled
+func (p *Processor) storeEval(key string, result *engine.SearchResult) {
+	if p.evalCache != nil && result != nil {
+		p.evalCache.put(key, result)
+	}
+}
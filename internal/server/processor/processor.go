@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -13,11 +14,51 @@ import (
 	"chess/internal/server/core"
 	"chess/internal/server/engine"
 	"chess/internal/server/game"
+	"chess/internal/server/pgn"
 	"chess/internal/server/service"
 )
 
 const (
-	minSearchTime = 100
+	minSearchTime     = 100
+	maxGameIDAttempts = 3
+
+	// DefaultMaxFENLength leaves headroom over the longest legal FEN (about 90 characters)
+	DefaultMaxFENLength = 100
+
+	defaultAnalysisDepth = 10
+	maxAnalysisDepth     = 18
+	maxConcurrentReviews = 2
+	hintSearchTime       = 500
+	maxHintStrength      = 20
+	gameEndSearchTime    = 100
+	fullSkillLevel       = 20
+	// Beyond this evaluation the game is decided, larger swings are not bigger mistakes
+	analysisEvalCap = 1000
+	mateScore       = 10000
+
+	// Time a create waiting for the first move allows beyond the search time
+	firstMoveWaitBuffer = 5 * time.Second
+
+	// Halfmove clock at which the fifty-move rule draws the game
+	fiftyMovePlies = 100
+
+	// Centipawn loss from which a graded move falls into the next grade
+	inaccuracyLoss = 50
+	mistakeLoss    = 100
+	blunderLoss    = 300
+
+	errEngineUnavailable = "engine unavailable"
+	errEngineBudget      = "engine time budget exhausted, retry later"
+	errEngineSaturated   = "engine queue saturated, retry later"
+
+	// DefaultEngineWorkers is the number of engines computing computer moves
+	DefaultEngineWorkers = 2
+
+	// DefaultValidationRetries is how often a failed validation engine reply is retried
+	DefaultValidationRetries = 2
+
+	// engineWarmupTimeout bounds how long the warmup log waits for slow engines
+	engineWarmupTimeout = time.Minute
 )
 
 // FEN validation regex
@@ -27,23 +68,324 @@ var fenPattern = regexp.MustCompile(`^[rnbqkpRNBQKP1-8/]+ [wb] [KQkq-]+ [a-h1-8-
 type Processor struct {
 	svc           *service.Service
 	queue         *EngineQueue
-	validationEng *engine.UCI // For synchronous move validation
+	newEngine     engine.Factory
+	validationEng engine.Engine // For synchronous move validation, nil without an engine
+	analysisSlots chan struct{}
+	maxFENLength  int
+	engineOptions map[string]bool // Allowlisted UCI option names, lowercase
+	syzygyPath    string
+	maxDepth      int            // Engine search depth ceiling, zero for none
+	budget        *engineBudget  // Engine time limits per game and client, nil when disabled
+	evalCache     *evalCache     // Engine results by position, nil when disabled
+	searchStreams *searchStreams // Progress of running computer searches, nil when disabled
+	requireClaim  bool
+	rejectOver    bool // Refuse new games from positions that are already decided
+	strictUndo    bool // Undo in a finished game needs force
+	strictPromo   bool // Reject a missing or superfluous promotion piece instead of correcting it
+	fenFallback   bool // Compute FENs natively when the engine times out reporting one
+	retries       int  // Validation engine retries after a failed or empty reply
+	nativeApply   bool // Apply computer moves with the native board instead of the validation engine
+	configureMove bool // Start the computer's move when configuring players hands it the turn
+	warmupGate    bool // Report unhealthy until the engine pool is warm
+	queueReject   int  // Queued computer moves above which new computer work is refused, zero disables
+	deadPlies     int  // Level evaluations in a row that draw a computer game, zero disables
+	deadHalfmove  int  // Halfmove clock the level evaluations also need
 	mu            sync.RWMutex
 }
 
 // New creates a processor with its own engine instances
-func New(svc *service.Service) (*Processor, error) {
+// Without an engine binary the processor runs degraded: human moves are
+// validated natively and computer players, analysis and hints are refused
+func New(svc *service.Service, engineWorkers int) (*Processor, error) {
+	return NewWithEngine(svc, engineWorkers, engine.NewEngine)
+}
+
+// NewWithEngine creates a processor whose validation, queue and analysis
+// engines all come from factory, so tests can run without an engine binary
+func NewWithEngine(svc *service.Service, engineWorkers int, factory engine.Factory) (*Processor, error) {
+	p := &Processor{
+		svc:           svc,
+		newEngine:     factory,
+		analysisSlots: make(chan struct{}, maxConcurrentReviews),
+		maxFENLength:  DefaultMaxFENLength,
+		fenFallback:   true,
+		retries:       DefaultValidationRetries,
+		nativeApply:   true,
+		configureMove: true,
+		strictPromo:   true,
+	}
+
 	// Create validation engine
-	validationEng, err := engine.New()
+	validationEng, err := factory()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create validation engine: %v", err)
+		log.Printf("Warning: engine unavailable, computer players disabled: %v", err)
+		return p, nil
 	}
+	p.validationEng = validationEng
+	p.queue = NewEngineQueue(engineWorkers, factory)
 
-	return &Processor{
-		svc:           svc,
-		queue:         NewEngineQueue(2), // 2 workers for computer moves
-		validationEng: validationEng,
-	}, nil
+	return p, nil
+}
+
+// EngineAvailable reports whether an engine was started for computer players
+func (p *Processor) EngineAvailable() bool {
+	return p.validationEng != nil
+}
+
+// EngineName returns the validation engine's identification, empty without an engine
+func (p *Processor) EngineName() string {
+	if !p.EngineAvailable() {
+		return ""
+	}
+	return p.validationEng.Name()
+}
+
+// EngineHealth returns the engine component status
+func (p *Processor) EngineHealth() string {
+	if p.EngineAvailable() {
+		return "ok"
+	}
+	return "unavailable"
+}
+
+// EnginePoolHealth returns the computer move engine pool status
+func (p *Processor) EnginePoolHealth() string {
+	switch {
+	case p.queue == nil:
+		return "unavailable"
+	case !p.queue.Warm():
+		return "warming"
+	case p.queue.ReadyWorkers() == 0:
+		return "unavailable"
+	case p.queue.ReadyWorkers() < p.queue.workers:
+		return "degraded"
+	}
+	return "ok"
+}
+
+// EngineQueueStats returns the engine pool load, zero without an engine
+func (p *Processor) EngineQueueStats() QueueStats {
+	if p.queue == nil {
+		return QueueStats{}
+	}
+	return p.queue.Stats()
+}
+
+// SetQueueSaturation reports the engine pool degraded in /health once more
+// than threshold computer moves stay queued for sustain, zero disables
+func (p *Processor) SetQueueSaturation(threshold int, sustain time.Duration) {
+	if p.queue != nil {
+		p.queue.SetSaturation(threshold, sustain)
+	}
+}
+
+// SetQueueReject refuses new computer games and computer moves while more
+// than depth computer moves are queued, zero disables
+func (p *Processor) SetQueueReject(depth int) {
+	p.queueReject = depth
+}
+
+// engineSaturated reports whether new computer work should be refused
+func (p *Processor) engineSaturated() bool {
+	return p.queueReject > 0 && p.queue != nil && p.queue.Stats().Queued > p.queueReject
+}
+
+// SetEngineWarmup reports the server unhealthy until every pool engine has
+// started and answered isready, and logs how long the warmup took
+func (p *Processor) SetEngineWarmup(enabled bool) {
+	p.warmupGate = enabled
+	if !enabled || p.queue == nil {
+		return
+	}
+	go func() {
+		elapsed, done := p.queue.WaitWarm(engineWarmupTimeout)
+		if !done {
+			log.Printf("Warning: engine pool still warming after %v", elapsed.Round(time.Millisecond))
+			return
+		}
+		log.Printf("Engine pool warm: %d/%d workers ready in %v",
+			p.queue.ReadyWorkers(), p.queue.workers, elapsed.Round(time.Millisecond))
+	}()
+}
+
+// Warming reports whether health checks should hold traffic for the engine pool
+func (p *Processor) Warming() bool {
+	return p.warmupGate && p.queue != nil && !p.queue.Warm()
+}
+
+// engineResponsive reports whether the engine is present and answers a readiness check
+func (p *Processor) engineResponsive() bool {
+	if !p.EngineAvailable() {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.validationEng.Ping(); err != nil {
+		log.Printf("Engine readiness check failed: %v", err)
+		return false
+	}
+	return true
+}
+
+// SetMaxFENLength sets the longest FEN accepted for new games
+func (p *Processor) SetMaxFENLength(n int) {
+	p.maxFENLength = n
+}
+
+// SetRejectFinishedPositions refuses new games from checkmate, stalemate or
+// insufficient material positions, by default they are created already ended
+func (p *Processor) SetRejectFinishedPositions(reject bool) {
+	p.rejectOver = reject
+}
+
+// SetStrictUndo requires an explicit force to undo moves of a finished game,
+// by default undo reopens finished games as in local analysis
+func (p *Processor) SetStrictUndo(strict bool) {
+	p.strictUndo = strict
+}
+
+// SetStrictPromotion rejects a pawn move to the last rank without a promotion
+// piece and a promotion piece on any other move, without it the former
+// promotes to a queen and the latter drops the piece
+func (p *Processor) SetStrictPromotion(strict bool) {
+	p.strictPromo = strict
+}
+
+// SetRequireClaim restricts human moves to authenticated users owning the slot,
+// the default lets anyone move for an unclaimed side as in local play
+func (p *Processor) SetRequireClaim(require bool) {
+	p.requireClaim = require
+}
+
+// SetFENFallback controls whether positions are computed natively when the
+// engine times out reporting a FEN, without it such moves are rejected
+func (p *Processor) SetFENFallback(enabled bool) {
+	p.fenFallback = enabled
+}
+
+// SetNativeApply controls whether computer moves are applied with the native
+// board, keeping the validation engine free, or validated by the engine
+func (p *Processor) SetNativeApply(enabled bool) {
+	p.nativeApply = enabled
+}
+
+// SetConfigureAutoMove controls whether changing the side to move from human
+// to computer starts the computer's move, without it clients send cccc
+func (p *Processor) SetConfigureAutoMove(enabled bool) {
+	p.configureMove = enabled
+}
+
+// SetValidationRetries sets how often a failed or empty validation engine
+// reply is retried after an isready sync, timeouts are not retried
+func (p *Processor) SetValidationRetries(retries int) {
+	p.retries = retries
+}
+
+// SetSyzygyPath enables Syzygy tablebases for computer players, analysis and hints
+func (p *Processor) SetSyzygyPath(path string) {
+	p.syzygyPath = path
+	if p.queue != nil {
+		p.queue.syzygyPath = path
+	}
+}
+
+// SetDeadPositionDraw draws computer versus computer games that cannot be won,
+// either because neither side has mating material or because the engine
+// evaluated plies moves in a row at 0.00 with the halfmove clock at halfmove
+func (p *Processor) SetDeadPositionDraw(plies, halfmove int) {
+	p.deadPlies = plies
+	p.deadHalfmove = halfmove
+}
+
+// SetEngineAffinity sends a game's computer moves to the worker that searched
+// its previous move when that worker is free, keeping its hash tables warm
+func (p *Processor) SetEngineAffinity(enabled bool) {
+	if p.queue != nil {
+		p.queue.affinity = enabled
+	}
+}
+
+// SetMaxDepth caps the search depth of every engine, bounding the work a
+// generous time budget can buy in simple positions, zero removes the cap
+func (p *Processor) SetMaxDepth(depth int) {
+	p.maxDepth = depth
+	if !p.EngineAvailable() {
+		return
+	}
+	p.queue.maxDepth = depth
+	p.mu.Lock()
+	p.validationEng.SetMaxDepth(depth)
+	p.mu.Unlock()
+}
+
+// SetEngineBudget limits the engine time each game and each client address may
+// consume within a rolling window, a zero budget disables the limit
+func (p *Processor) SetEngineBudget(budget, window time.Duration) {
+	if budget <= 0 {
+		p.budget = nil
+		return
+	}
+	p.budget = newEngineBudget(budget, window)
+}
+
+// withinEngineBudget reports whether the game and client can spend estimate more engine time
+func (p *Processor) withinEngineBudget(gameID, clientIP string, estimate time.Duration) bool {
+	return p.budget == nil || p.budget.allow(estimate, budgetKeys(gameID, clientIP)...)
+}
+
+// chargeEngineTime records engine time spent on behalf of a game and client
+func (p *Processor) chargeEngineTime(gameID, clientIP string, spent time.Duration) {
+	if p.budget != nil {
+		p.budget.charge(spent, budgetKeys(gameID, clientIP)...)
+	}
+}
+
+// analysisDepthLimit is the deepest analysis a request may ask for
+func (p *Processor) analysisDepthLimit() int {
+	if p.maxDepth > 0 {
+		return min(maxAnalysisDepth, p.maxDepth)
+	}
+	return maxAnalysisDepth
+}
+
+// newAnalysisEngine starts a dedicated engine for analysis or hints, reporting
+// win/draw/loss statistics when tablebases are configured
+func (p *Processor) newAnalysisEngine() (engine.Engine, error) {
+	eng, err := p.newEngine()
+	if err != nil {
+		return nil, err
+	}
+	eng.SetMaxDepth(p.maxDepth)
+	if p.syzygyPath != "" {
+		eng.SetSyzygyPath(p.syzygyPath)
+		eng.SetOption("UCI_ShowWDL", "true")
+	}
+	return eng, nil
+}
+
+// serverEngineOptions are UCI options configured by server flags, lowercase
+var serverEngineOptions = map[string]bool{
+	"syzygypath": true, // -syzygy-path
+}
+
+// SetEngineOptionAllowlist sets the UCI option names players may configure,
+// matched case-insensitively as engines do, none are allowed by default
+// Options the server sets itself are skipped, a player's value would be reset
+// to the engine default for the next player and lose the server's setting
+func (p *Processor) SetEngineOptionAllowlist(names []string) {
+	p.engineOptions = make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			if serverEngineOptions[strings.ToLower(name)] {
+				log.Printf("Warning: engine option %q is set by the server and cannot be allowed", name)
+				continue
+			}
+			p.engineOptions[strings.ToLower(name)] = true
+			if p.EngineAvailable() && !p.validationEng.SupportsOption(name) {
+				log.Printf("Warning: engine option %q is allowed but not supported by %s", name, p.validationEng.Name())
+			}
+		}
+	}
 }
 
 func (p *Processor) Execute(cmd Command) ProcessorResponse {
@@ -52,66 +394,112 @@ func (p *Processor) Execute(cmd Command) ProcessorResponse {
 		return p.handleCreateGame(cmd)
 	case CmdConfigurePlayers:
 		return p.handleConfigurePlayers(cmd)
+	case CmdGetPlayers:
+		return p.handleGetPlayers(cmd)
 	case CmdGetGame:
 		return p.handleGetGame(cmd)
+	case CmdGetTurn:
+		return p.handleGetTurn(cmd)
 	case CmdMakeMove:
 		return p.handleMakeMove(cmd)
+	case CmdMakeMoves:
+		return p.handleMakeMoves(cmd)
 	case CmdUndoMove:
 		return p.handleUndoMove(cmd)
+	case CmdResetGame:
+		return p.handleResetGame(cmd)
+	case CmdRematch:
+		return p.handleRematch(cmd)
+	case CmdRecoverGame:
+		return p.handleRecoverGame(cmd)
+	case CmdAbortGame:
+		return p.handleAbortGame(cmd)
 	case CmdDeleteGame:
 		return p.handleDeleteGame(cmd)
 	case CmdGetBoard:
 		return p.handleGetBoard(cmd)
+	case CmdGetScoresheet:
+		return p.handleGetScoresheet(cmd)
+	case CmdAnalyzeGame:
+		return p.handleAnalyzeGame(cmd)
+	case CmdGetHint:
+		return p.handleGetHint(cmd)
+	case CmdGradeMove:
+		return p.handleGradeMove(cmd)
 	default:
 		return p.errorResponse("unknown command", core.ErrInvalidRequest)
 	}
 }
 
-// isFENSafe check for control characters that could inject UCI commands and FEN pattern match
-func (p *Processor) isFENSafe(fen string) bool {
+// validateFEN rejects FENs that are too long, could inject UCI commands, or do not
+// describe exactly 64 squares, before they reach the engine
+func (p *Processor) validateFEN(fen string) error {
+	if len(fen) > p.maxFENLength {
+		return fmt.Errorf("exceeds %d characters", p.maxFENLength)
+	}
+
 	// Check for control characters
-	for _, r := range fen {
-		if unicode.IsControl(r) && r != ' ' {
-			return false
-		}
+	if hasControlChars(fen) {
+		return fmt.Errorf("contains control characters")
 	}
 
 	// Validate FEN format
-	return fenPattern.MatchString(fen)
-}
+	if !fenPattern.MatchString(fen) {
+		return fmt.Errorf("invalid format or characters")
+	}
 
-func (p *Processor) isMoveSafe(move string) bool {
-	// Check for control characters
-	for _, r := range move {
-		if unicode.IsControl(r) {
-			return false
+	// Each rank must expand to 8 squares, which also bounds the piece count
+	placement, _, _ := strings.Cut(fen, " ")
+	ranks := strings.Split(placement, "/")
+	if len(ranks) != 8 {
+		return fmt.Errorf("board has %d ranks, expected 8", len(ranks))
+	}
+	for i, rank := range ranks {
+		squares := 0
+		for _, c := range rank {
+			if c >= '1' && c <= '8' {
+				squares += int(c - '0')
+			} else {
+				squares++
+			}
+		}
+		if squares != 8 {
+			return fmt.Errorf("rank %d has %d squares, expected 8", 8-i, squares)
 		}
 	}
 
-	// UCI valid moves are 4-5 characters only
-	// Examples: e2e4 / e1g1 (castle) / a7a8q (promotion)
-	// UCI moves: [a-h][1-8][a-h][1-8][qrbn]?
-	if len(move) < 4 || len(move) > 5 {
-		return false
-	}
+	return nil
+}
 
-	// Check each character
-	if move[0] < 'a' || move[0] > 'h' ||
-		move[1] < '1' || move[1] > '8' ||
-		move[2] < 'a' || move[2] > 'h' ||
-		move[3] < '1' || move[3] > '8' {
+func (p *Processor) isMoveSafe(move string) bool {
+	// Check for control characters
+	if hasControlChars(move) {
 		return false
 	}
 
-	// Promotion piece if present
-	if len(move) == 5 {
-		promotion := move[4]
-		if promotion != 'q' && promotion != 'r' && promotion != 'b' && promotion != 'n' {
-			return false
+	return core.IsUCIMove(move)
+}
+
+// hasControlChars reports characters that could end a UCI command line early
+func hasControlChars(s string) bool {
+	return strings.ContainsFunc(s, unicode.IsControl)
+}
+
+// validateEngineOptions rejects UCI options missing from the server allowlist
+// or carrying characters that could inject further engine commands
+func (p *Processor) validateEngineOptions(options map[string]string) error {
+	for name, value := range options {
+		if !p.engineOptions[strings.ToLower(name)] {
+			return fmt.Errorf("engine option %q is not allowed", name)
+		}
+		if p.EngineAvailable() && !p.validationEng.SupportsOption(name) {
+			return fmt.Errorf("engine option %q is not supported by %s", name, p.validationEng.Name())
+		}
+		if hasControlChars(name) || hasControlChars(value) {
+			return fmt.Errorf("engine option %q contains control characters", name)
 		}
 	}
-
-	return true
+	return nil
 }
 
 // handleCreateGame creates a new game and triggers computer move if needed
@@ -129,32 +517,22 @@ func (p *Processor) handleCreateGame(cmd Command) ProcessorResponse {
 		args.Black.SearchTime = minSearchTime
 	}
 
-	// Check computer game limit
-	hasComputer := args.White.Type == core.PlayerComputer || args.Black.Type == core.PlayerComputer
-	if hasComputer && !p.svc.CanCreateComputerGame() {
-		return p.errorResponse(
-			fmt.Sprintf("computer game limit reached (%d/%d)", p.svc.GetComputerGameCount(), service.MaxComputerGames),
-			core.ErrResourceLimit,
-		)
+	for _, cfg := range []core.PlayerConfig{args.White, args.Black} {
+		if err := p.validateEngineOptions(cfg.EngineOptions); err != nil {
+			return p.errorResponse(err.Error(), core.ErrInvalidRequest)
+		}
 	}
 
-	// Generate game ID
-	gameID := p.svc.GenerateGameID()
-
 	// Validate and canonicalize FEN if provided
 	initialFEN := board.StartingFEN
 	if args.FEN != "" {
-		if !p.isFENSafe(args.FEN) {
-			return p.errorResponse("invalid FEN format or characters", core.ErrInvalidFEN)
+		if err := p.validateFEN(args.FEN); err != nil {
+			return p.errorResponse(fmt.Sprintf("invalid FEN: %v", err), core.ErrInvalidFEN)
 		}
 		initialFEN = args.FEN
 	}
 
-	p.mu.Lock()
-	p.validationEng.NewGame()
-	p.validationEng.SetPosition(initialFEN, []string{})
-	validatedFEN, err := p.validationEng.GetFEN()
-	p.mu.Unlock()
+	validatedFEN, err := p.canonicalFEN(initialFEN)
 
 	if err != nil {
 		return p.errorResponse(fmt.Sprintf("invalid FEN: %v", err), core.ErrInvalidRequest)
@@ -165,6 +543,14 @@ func (p *Processor) handleCreateGame(cmd Command) ProcessorResponse {
 	if err != nil {
 		return p.errorResponse(fmt.Sprintf("FEN parse error: %v", err), core.ErrInvalidRequest)
 	}
+	if err := checkNormalizedFEN(initialFEN, b); err != nil {
+		return p.errorResponse(fmt.Sprintf("invalid FEN: %v", err), core.ErrInvalidFEN)
+	}
+	if p.rejectOver {
+		if reason := finishedReason(b); reason != "" {
+			return p.errorResponse("invalid FEN: position is already over by "+strings.ReplaceAll(reason, "_", " "), core.ErrInvalidFEN)
+		}
+	}
 
 	// Create players with appropriate IDs
 	whitePlayer := core.NewPlayer(args.White, core.ColorWhite)
@@ -183,13 +569,111 @@ func (p *Processor) handleCreateGame(cmd Command) ProcessorResponse {
 		}
 	}
 
-	// Create game in service with fully-formed players
-	if err = p.svc.CreateGame(gameID, whitePlayer, blackPlayer, validatedFEN, b.Turn()); err != nil {
-		return p.errorResponse(fmt.Sprintf("failed to create game: %v", err), core.ErrInternalError)
+	// Waiting for the first move starts it
+	args.AutoStart = args.AutoStart || args.WaitFirstMove
+
+	// Refuse before creating the game so an autostart never leaves it pending without a search
+	if args.AutoStart {
+		first := whitePlayer
+		if b.Turn() == core.ColorBlack {
+			first = blackPlayer
+		}
+		if first.Type == core.PlayerComputer && p.EngineAvailable() &&
+			!p.withinEngineBudget("", cmd.ClientIP, searchDuration(first)) {
+			return p.errorResponse(errEngineBudget, core.ErrRateLimitExceeded)
+		}
+	}
+
+	resp := p.startGame(whitePlayer, blackPlayer, validatedFEN, b.Turn(), "")
+	if !resp.Success || !args.AutoStart {
+		return resp
+	}
+
+	// Start the computer's first move so clients only need to poll
+	gameID := resp.Data.(core.GameResponse).GameID
+	g, err := p.svc.GetGame(gameID)
+	if err != nil || g.State() != core.StateOngoing || g.NextPlayer().Type != core.PlayerComputer {
+		return resp
+	}
+
+	// Read before the move is submitted, the engine callback writes the game
+	wait := min(searchDuration(g.NextPlayer())+firstMoveWaitBuffer, service.WaitTimeout)
+	moverColor := g.NextTurnColor()
+
+	p.svc.UpdateGameState(gameID, core.StatePending)
+	done := p.triggerComputerMove(gameID, g, cmd.ClientIP)
+
+	// A waiting client gets the game after the first move, or still pending
+	// if the move takes longer than its search time allows
+	if args.WaitFirstMove {
+		select {
+		case <-done:
+		case <-time.After(wait):
+		}
+		if g, err := p.svc.GetGame(gameID); err == nil && g.State() != core.StatePending {
+			return ProcessorResponse{
+				Success: true,
+				Data:    p.buildGameResponse(gameID, g, core.MoveWindow{}),
+			}
+		}
+	}
+
+	g, _ = p.svc.GetGame(gameID)
+	response := p.buildGameResponse(gameID, g, core.MoveWindow{})
+	response.LastMove = &core.MoveInfo{
+		PlayerColor: moverColor.String(),
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Pending: true,
+		Data:    response,
+	}
+}
+
+// startGame registers a game with fully-formed players and a validated FEN
+// previousGameID links a rematch to its source game, empty for new games
+func (p *Processor) startGame(whitePlayer, blackPlayer *core.Player, fen string, turn core.Color, previousGameID string) ProcessorResponse {
+	// Check computer game limit
+	hasComputer := whitePlayer.Type == core.PlayerComputer || blackPlayer.Type == core.PlayerComputer
+	if hasComputer && !p.EngineAvailable() {
+		return p.errorResponse(errEngineUnavailable, core.ErrInvalidRequest)
+	}
+	if hasComputer && p.engineSaturated() {
+		return p.errorResponse(errEngineSaturated, core.ErrEngineSaturated)
+	}
+	if hasComputer && !p.svc.CanCreateComputerGame() {
+		return p.errorResponse(
+			fmt.Sprintf("computer game limit reached (%d/%d)", p.svc.GetComputerGameCount(), service.MaxComputerGames),
+			core.ErrResourceLimit,
+		)
+	}
+
+	// Generate game ID
+	gameID := p.svc.GenerateGameID()
+
+	// Create game in service with fully-formed players, regenerating the ID
+	// if another instance sharing storage claimed it in the meantime
+	for attempt := 1; ; attempt++ {
+		err := p.svc.CreateGame(gameID, whitePlayer, blackPlayer, fen, turn)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, service.ErrGameCapacity) {
+			return p.errorResponse(err.Error(), core.ErrResourceLimit)
+		}
+		if !errors.Is(err, service.ErrGameExists) || attempt == maxGameIDAttempts {
+			return p.errorResponse(fmt.Sprintf("failed to create game: %v", err), core.ErrInternalError)
+		}
+		gameID = p.svc.GenerateGameID()
+	}
+
+	if previousGameID != "" {
+		p.svc.SetPreviousGame(gameID, previousGameID)
 	}
 
 	// Check if the initial FEN represents a completed game
-	p.checkGameEnd(gameID, validatedFEN, core.OppositeColor(b.Turn()))
+	p.checkInitialPosition(gameID, fen)
 
 	// Get created game
 	g, err := p.svc.GetGame(gameID)
@@ -198,7 +682,7 @@ func (p *Processor) handleCreateGame(cmd Command) ProcessorResponse {
 	}
 
 	// Build response
-	response := p.buildGameResponse(gameID, g)
+	response := p.buildGameResponse(gameID, g, core.MoveWindow{})
 
 	return ProcessorResponse{
 		Success: true,
@@ -206,6 +690,32 @@ func (p *Processor) handleCreateGame(cmd Command) ProcessorResponse {
 	}
 }
 
+// handleRematch starts a new game from a finished one with the players' colors swapped
+func (p *Processor) handleRematch(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	if !g.State().IsGameOver() {
+		return p.errorResponse("rematch is only available after the game has ended", core.ErrInvalidRequest)
+	}
+
+	// Same players keep their IDs and claims on the opposite side
+	whitePlayer := *g.GetPlayer(core.ColorBlack)
+	whitePlayer.Color = core.ColorWhite
+	blackPlayer := *g.GetPlayer(core.ColorWhite)
+	blackPlayer.Color = core.ColorBlack
+
+	initialFEN := g.InitialFEN()
+	b, err := board.ParseFEN(initialFEN)
+	if err != nil {
+		return p.errorResponse(fmt.Sprintf("FEN parse error: %v", err), core.ErrInternalError)
+	}
+
+	return p.startGame(&whitePlayer, &blackPlayer, initialFEN, b.Turn(), cmd.GameID)
+}
+
 // handleConfigurePlayers updates player configuration mid-game
 func (p *Processor) handleConfigurePlayers(cmd Command) ProcessorResponse {
 	args, ok := cmd.Args.(core.ConfigurePlayersRequest)
@@ -220,15 +730,33 @@ func (p *Processor) handleConfigurePlayers(cmd Command) ProcessorResponse {
 		args.Black.SearchTime = minSearchTime
 	}
 
+	for _, cfg := range []core.PlayerConfig{args.White, args.Black} {
+		if err := p.validateEngineOptions(cfg.EngineOptions); err != nil {
+			return p.errorResponse(err.Error(), core.ErrInvalidRequest)
+		}
+	}
+	if (args.White.Type == core.PlayerComputer || args.Black.Type == core.PlayerComputer) && !p.EngineAvailable() {
+		return p.errorResponse(errEngineUnavailable, core.ErrInvalidRequest)
+	}
+
 	g, err := p.svc.GetGame(cmd.GameID)
 	if err != nil {
 		return p.errorResponse("game not found", core.ErrGameNotFound)
 	}
 
-	// Block configuration changes during computer move
+	// Players change only between moves: never during a computer move, and
+	// not after a move the client has not seen yet
 	if g.State() == core.StatePending {
 		return p.errorResponse("cannot change players while computer is calculating", core.ErrInvalidRequest)
 	}
+	moveCount := len(g.Moves())
+	if args.ExpectedMoveCount != nil && *args.ExpectedMoveCount != moveCount {
+		return p.errorResponse(
+			fmt.Sprintf("game has %d moves, expected %d - refetch game state", moveCount, *args.ExpectedMoveCount),
+			core.ErrMoveConflict,
+		)
+	}
+	wasHuman := g.NextPlayer().Type == core.PlayerHuman
 
 	// Create new player instances
 	whitePlayer := core.NewPlayer(args.White, core.ColorWhite)
@@ -241,7 +769,29 @@ func (p *Processor) handleConfigurePlayers(cmd Command) ProcessorResponse {
 
 	// Get updated game
 	g, _ = p.svc.GetGame(cmd.GameID)
-	response := p.buildGameResponse(cmd.GameID, g)
+
+	// The turn is unchanged, a human to move handed to the computer moves now
+	currentPlayer := g.NextPlayer()
+	if p.configureMove && wasHuman && currentPlayer.Type == core.PlayerComputer &&
+		g.State() == core.StateOngoing && !p.engineSaturated() &&
+		p.withinEngineBudget(cmd.GameID, cmd.ClientIP, searchDuration(currentPlayer)) {
+		p.svc.UpdateGameState(cmd.GameID, core.StatePending)
+		p.triggerComputerMove(cmd.GameID, g, cmd.ClientIP)
+
+		g, _ = p.svc.GetGame(cmd.GameID)
+		response := p.buildGameResponse(cmd.GameID, g, core.MoveWindow{})
+		response.LastMove = &core.MoveInfo{
+			PlayerColor: g.NextTurnColor().String(),
+		}
+
+		return ProcessorResponse{
+			Success: true,
+			Pending: true,
+			Data:    response,
+		}
+	}
+
+	response := p.buildGameResponse(cmd.GameID, g, core.MoveWindow{})
 
 	return ProcessorResponse{
 		Success: true,
@@ -249,6 +799,22 @@ func (p *Processor) handleConfigurePlayers(cmd Command) ProcessorResponse {
 	}
 }
 
+// handleGetPlayers returns both players with their engine settings
+func (p *Processor) handleGetPlayers(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Data: core.PlayersResponse{
+			White: g.GetPlayer(core.ColorWhite),
+			Black: g.GetPlayer(core.ColorBlack),
+		},
+	}
+}
+
 // handleGetGame retrieves game state and triggers computer move if needed
 func (p *Processor) handleGetGame(cmd Command) ProcessorResponse {
 	g, err := p.svc.GetGame(cmd.GameID)
@@ -256,7 +822,8 @@ func (p *Processor) handleGetGame(cmd Command) ProcessorResponse {
 		return p.errorResponse("game not found", core.ErrGameNotFound)
 	}
 
-	response := p.buildGameResponse(cmd.GameID, g)
+	window, _ := cmd.Args.(core.MoveWindow)
+	response := p.buildGameResponse(cmd.GameID, g, window)
 
 	return ProcessorResponse{
 		Success: true,
@@ -264,6 +831,23 @@ func (p *Processor) handleGetGame(cmd Command) ProcessorResponse {
 	}
 }
 
+// handleGetTurn reports turn, state and move count for lightweight polling
+func (p *Processor) handleGetTurn(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Data: core.TurnResponse{
+			Turn:      g.NextTurnColor().String(),
+			State:     g.State().String(),
+			MoveCount: g.MoveCount(),
+		},
+	}
+}
+
 // handleMakeMove processes human moves with authorization
 func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 	args, ok := cmd.Args.(core.MoveRequest)
@@ -282,7 +866,7 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 		return p.errorResponse("computer move in progress", core.ErrInvalidRequest)
 	case core.StateStuck:
 		return p.errorResponse("game is stuck due to engine error", core.ErrGameOver)
-	case core.StateWhiteWins, core.StateBlackWins, core.StateDraw, core.StateStalemate:
+	case core.StateWhiteWins, core.StateBlackWins, core.StateDraw, core.StateStalemate, core.StateAborted:
 		return p.errorResponse(fmt.Sprintf("game is over: %s", g.State()), core.ErrGameOver)
 	case core.StateOngoing:
 		break
@@ -290,6 +874,15 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 		return p.errorResponse("game is in invalid state", core.ErrInvalidRequest)
 	}
 
+	// Optimistic concurrency: reject if the client's view of the game is stale
+	moveCount := len(g.Moves())
+	if args.ExpectedMoveCount != nil && *args.ExpectedMoveCount != moveCount {
+		return p.errorResponse(
+			fmt.Sprintf("game has %d moves, expected %d - refetch game state", moveCount, *args.ExpectedMoveCount),
+			core.ErrMoveConflict,
+		)
+	}
+
 	currentColor := g.NextTurnColor()
 	currentPlayer := g.NextPlayer()
 
@@ -298,12 +891,21 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 		if currentPlayer.Type != core.PlayerComputer {
 			return p.errorResponse("not computer player's turn", core.ErrNotHumanTurn)
 		}
+		if !p.EngineAvailable() {
+			return p.errorResponse(errEngineUnavailable, core.ErrInvalidRequest)
+		}
+		if !p.withinEngineBudget(cmd.GameID, cmd.ClientIP, searchDuration(currentPlayer)) {
+			return p.errorResponse(errEngineBudget, core.ErrRateLimitExceeded)
+		}
+		if p.engineSaturated() {
+			return p.errorResponse(errEngineSaturated, core.ErrEngineSaturated)
+		}
 
 		p.svc.UpdateGameState(cmd.GameID, core.StatePending)
-		p.triggerComputerMove(cmd.GameID, g)
+		p.triggerComputerMove(cmd.GameID, g, cmd.ClientIP)
 
 		g, _ = p.svc.GetGame(cmd.GameID)
-		response := p.buildGameResponse(cmd.GameID, g)
+		response := p.buildGameResponse(cmd.GameID, g, core.MoveWindow{})
 		response.LastMove = &core.MoveInfo{
 			PlayerColor: currentColor.String(),
 		}
@@ -323,6 +925,17 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 	// Authorization: first-move-claims-slot model
 	slotOwner := g.GetSlotOwner(currentColor)
 
+	// Online play: every human move comes from the authenticated owner of the slot,
+	// and one user cannot claim both sides
+	if p.requireClaim {
+		if cmd.UserID == "" {
+			return p.errorResponse("authentication required to move", core.ErrUnauthorized)
+		}
+		if slotOwner == "" && g.IsSlotClaimedBy(core.OppositeColor(currentColor), cmd.UserID) {
+			return p.errorResponse("not your turn - you play the other side", core.ErrUnauthorized)
+		}
+	}
+
 	if slotOwner == "" {
 		// Slot unclaimed - claim it with this move
 		if cmd.UserID != "" {
@@ -349,18 +962,44 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 
 	currentFEN := g.CurrentFEN()
 
-	// Validate move with engine
-	p.mu.Lock()
-	p.validationEng.SetPosition(currentFEN, []string{move})
-	newFEN, err := p.validationEng.GetFEN()
-	p.mu.Unlock()
+	// A piece of the side not to move or a mismatched promotion piece is a
+	// clearer error than the engine's rejection
+	if b, err := board.ParseFEN(currentFEN); err == nil {
+		if color, ok := b.ColorAt(move[:2]); ok && color != b.Turn() {
+			resp := p.errorResponse("illegal move", core.ErrInvalidMove)
+			resp.Error.Details = fmt.Sprintf("not your turn to move this piece: %s holds a %s piece, %s to move",
+				move[:2], color.Name(), b.Turn().Name())
+			return resp
+		}
 
-	if err != nil || newFEN == currentFEN {
-		return p.errorResponse("illegal move", core.ErrInvalidMove)
+		promoting, hasPiece := b.IsPromotion(move), len(move) == 5
+		switch {
+		case promoting && !hasPiece && p.strictPromo:
+			resp := p.errorResponse("illegal move", core.ErrInvalidMove)
+			resp.Error.Details = fmt.Sprintf("%s promotes a pawn and needs a promotion piece (q, r, b or n), e.g. %sq", move, move)
+			return resp
+		case !promoting && hasPiece && p.strictPromo:
+			resp := p.errorResponse("illegal move", core.ErrInvalidMove)
+			resp.Error.Details = fmt.Sprintf("%s is not a promotion, only a pawn reaching the last rank takes a promotion piece", move)
+			return resp
+		case promoting && !hasPiece:
+			move += "q"
+		case !promoting && hasPiece:
+			move = move[:4]
+		}
 	}
 
-	// Apply move to game state via service
-	if err = p.svc.ApplyMove(cmd.GameID, move, newFEN); err != nil {
+	newFEN, err := p.positionAfter(currentFEN, move)
+
+	if err != nil || newFEN == currentFEN {
+		return p.errorResponse("illegal move", core.ErrInvalidMove)
+	}
+
+	// Apply move only if no other move landed since validation
+	if err = p.svc.ApplyMove(cmd.GameID, move, newFEN, moveCount); err != nil {
+		if errors.Is(err, service.ErrMoveCountMismatch) {
+			return p.errorResponse("game changed while move was processed - refetch game state", core.ErrMoveConflict)
+		}
 		return p.errorResponse(fmt.Sprintf("failed to apply move: %v", err), core.ErrInternalError)
 	}
 
@@ -373,10 +1012,11 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 
 	// Check for checkmate/stalemate
 	p.checkGameEnd(cmd.GameID, newFEN, currentColor)
+	p.checkDrawRules(cmd.GameID)
 
 	// Get updated game
 	g, _ = p.svc.GetGame(cmd.GameID)
-	response := p.buildGameResponse(cmd.GameID, g)
+	response := p.buildGameResponse(cmd.GameID, g, core.MoveWindow{})
 
 	// Add human move info
 	response.LastMove = &core.MoveInfo{
@@ -390,6 +1030,38 @@ func (p *Processor) handleMakeMove(cmd Command) ProcessorResponse {
 	}
 }
 
+// handleMakeMoves applies a sequence of human moves in order
+// Moves before a failing one stay applied; the error names the failing index
+func (p *Processor) handleMakeMoves(cmd Command) ProcessorResponse {
+	args, ok := cmd.Args.(core.BatchMoveRequest)
+	if !ok {
+		return p.errorResponse("invalid arguments", core.ErrInvalidRequest)
+	}
+
+	var resp ProcessorResponse
+	for i, move := range args.Moves {
+		req := core.MoveRequest{Move: move}
+		// The expected count guards the batch as a whole, later moves build on it
+		if i == 0 {
+			req.ExpectedMoveCount = args.ExpectedMoveCount
+		}
+
+		resp = p.handleMakeMove(Command{
+			Type:     CmdMakeMove,
+			UserID:   cmd.UserID,
+			GameID:   cmd.GameID,
+			ClientIP: cmd.ClientIP,
+			Args:     req,
+		})
+		if !resp.Success {
+			resp.Error.Error = fmt.Sprintf("moves[%d] (%s): %s", i, move, resp.Error.Error)
+			return resp
+		}
+	}
+
+	return resp
+}
+
 // handleUndoMove reverts game state
 func (p *Processor) handleUndoMove(cmd Command) ProcessorResponse {
 	g, err := p.svc.GetGame(cmd.GameID)
@@ -403,6 +1075,8 @@ func (p *Processor) handleUndoMove(cmd Command) ProcessorResponse {
 		return p.errorResponse("cannot undo while computer move is in progress", core.ErrInvalidRequest)
 	case core.StateStuck:
 		return p.errorResponse("cannot undo in stuck game", core.ErrInvalidRequest)
+	case core.StateAborted:
+		return p.errorResponse("cannot undo in aborted game", core.ErrGameOver)
 	}
 
 	args := core.UndoRequest{Count: 1}
@@ -411,8 +1085,27 @@ func (p *Processor) handleUndoMove(cmd Command) ProcessorResponse {
 			args = req
 		}
 	}
+	if args.Count < 1 {
+		args.Count = 1
+	}
+	if args.Count > core.MaxUndoCount {
+		return p.errorResponse(fmt.Sprintf("undo count %d exceeds the maximum of %d", args.Count, core.MaxUndoCount), core.ErrInvalidRequest)
+	}
+	if p.strictUndo && g.State().IsGameOver() && !args.Force {
+		return p.errorResponse("game is over, set force to undo", core.ErrGameOver)
+	}
 
-	if err = p.svc.UndoMoves(cmd.GameID, args.Count); err != nil {
+	// Against a computer, stop on the human's turn so the move can be retried
+	// instead of the computer immediately replaying its reply
+	count := args.Count
+	if (args.Smart == nil || *args.Smart) && isHumanVsComputer(g) {
+		moves := g.MoveCount()
+		if count < moves && g.GetPlayer(g.TurnAfter(moves-count)).Type == core.PlayerComputer {
+			count++
+		}
+	}
+
+	if err = p.svc.UndoMoves(cmd.GameID, count); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			return p.errorResponse("game not found", core.ErrGameNotFound)
 		}
@@ -423,7 +1116,39 @@ func (p *Processor) handleUndoMove(cmd Command) ProcessorResponse {
 	p.svc.UpdateGameState(cmd.GameID, core.StateOngoing)
 
 	g, _ = p.svc.GetGame(cmd.GameID)
-	response := p.buildGameResponse(cmd.GameID, g)
+	response := p.buildGameResponse(cmd.GameID, g, core.MoveWindow{})
+
+	return ProcessorResponse{
+		Success: true,
+		Data:    response,
+	}
+}
+
+// isHumanVsComputer reports whether exactly one side is played by the engine
+func isHumanVsComputer(g *game.Game) bool {
+	return (g.GetPlayer(core.ColorWhite).Type == core.PlayerComputer) != (g.GetPlayer(core.ColorBlack).Type == core.PlayerComputer)
+}
+
+// handleResetGame returns a game to its starting position
+func (p *Processor) handleResetGame(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	if g.State() == core.StatePending {
+		return p.errorResponse("cannot reset while computer move is in progress", core.ErrInvalidRequest)
+	}
+	if g.State() == core.StateAborted {
+		return p.errorResponse("cannot reset aborted game", core.ErrGameOver)
+	}
+
+	if err = p.svc.ResetGame(cmd.GameID); err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	g, _ = p.svc.GetGame(cmd.GameID)
+	response := p.buildGameResponse(cmd.GameID, g, core.MoveWindow{})
 
 	return ProcessorResponse{
 		Success: true,
@@ -431,6 +1156,60 @@ func (p *Processor) handleUndoMove(cmd Command) ProcessorResponse {
 	}
 }
 
+// handleRecoverGame resets a stuck game to ongoing so the side to move can play again
+// The failed computer move was never applied, so only the state changes
+func (p *Processor) handleRecoverGame(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	if g.State() != core.StateStuck {
+		return p.errorResponse(fmt.Sprintf("game is not stuck: %s", g.State()), core.ErrInvalidRequest)
+	}
+
+	// Recovering into the same failure would only get the game stuck again
+	if !p.engineResponsive() {
+		return p.errorResponse(errEngineUnavailable, core.ErrResourceLimit)
+	}
+
+	if err = p.svc.UpdateGameState(cmd.GameID, core.StateOngoing); err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	g, _ = p.svc.GetGame(cmd.GameID)
+	return ProcessorResponse{
+		Success: true,
+		Data:    p.buildGameResponse(cmd.GameID, g, core.MoveWindow{}),
+	}
+}
+
+// handleAbortGame terminates an unfinished game without a winner, unlike delete
+// the game and its moves stay stored and queryable
+func (p *Processor) handleAbortGame(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	if g.State() == core.StatePending {
+		return p.errorResponse("cannot abort while computer move is in progress", core.ErrInvalidRequest)
+	}
+	if g.State().IsGameOver() {
+		return p.errorResponse(fmt.Sprintf("game is over: %s", g.State()), core.ErrGameOver)
+	}
+
+	if err = p.svc.EndGame(cmd.GameID, core.StateAborted, core.ReasonAborted); err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	g, _ = p.svc.GetGame(cmd.GameID)
+	return ProcessorResponse{
+		Success: true,
+		Data:    p.buildGameResponse(cmd.GameID, g, core.MoveWindow{}),
+	}
+}
+
 // handleDeleteGame removes a game
 func (p *Processor) handleDeleteGame(cmd Command) ProcessorResponse {
 	g, err := p.svc.GetGame(cmd.GameID)
@@ -446,6 +1225,9 @@ func (p *Processor) handleDeleteGame(cmd Command) ProcessorResponse {
 	if err = p.svc.DeleteGame(cmd.GameID); err != nil {
 		return p.errorResponse("game not found", core.ErrGameNotFound)
 	}
+	if p.queue != nil {
+		p.queue.Forget(cmd.GameID)
+	}
 
 	return ProcessorResponse{
 		Success: true,
@@ -463,25 +1245,417 @@ func (p *Processor) handleGetBoard(cmd Command) ProcessorResponse {
 	if err != nil {
 		return p.errorResponse("error parsing FEN", core.ErrInvalidFEN)
 	}
-	ascii := b.ToASCII()
+	args, _ := cmd.Args.(boardArgs)
+
+	resp := core.BoardResponse{
+		FEN:       g.CurrentFEN(),
+		Board:     b.ToASCII(args.opts),
+		Castling:  b.Castling(),
+		EnPassant: b.EnPassant(),
+		HalfMove:  b.HalfMove(),
+		FullMove:  b.FullMove(),
+	}
+
+	if args.status {
+		// Endings the board cannot show, such as resignation or repetition, come from the game state
+		inCheck := b.InCheck()
+		reason := g.Reason()
+		if !g.State().IsGameOver() {
+			reason = ""
+			if b.IsCheckmate() {
+				reason = core.ReasonCheckmate
+			} else if b.IsStalemate() {
+				reason = core.ReasonStalemate
+			}
+		}
+		gameOver := reason != "" || g.State().IsGameOver()
+		resp.InCheck, resp.GameOver, resp.Reason = &inCheck, &gameOver, reason
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Data:    resp,
+	}
+}
+
+// handleGetScoresheet converts the move history to numbered SAN pairs
+func (p *Processor) handleGetScoresheet(cmd Command) ProcessorResponse {
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	sans, err := pgn.MovesToSAN(g.InitialFEN(), g.Moves())
+	if err != nil {
+		return p.errorResponse(fmt.Sprintf("failed to replay moves: %v", err), core.ErrInternalError)
+	}
+
+	lines := make([]core.ScoresheetLine, 0, len(sans)/2+1)
+	for _, pair := range pgn.Pair(g.InitialFEN(), sans) {
+		lines = append(lines, core.ScoresheetLine{
+			Number: pair.Number,
+			White:  pair.White,
+			Black:  pair.Black,
+		})
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Data:    lines,
+	}
+}
+
+// handleAnalyzeGame evaluates every position of a game at a fixed depth
+// Analysis runs on its own engine instance so it does not hold up move validation
+func (p *Processor) handleAnalyzeGame(cmd Command) ProcessorResponse {
+	// Without a requested depth the default is lowered to the server's ceiling,
+	// only depths the client chose are range checked
+	depth, _ := cmd.Args.(int)
+	if depth == 0 {
+		depth = min(defaultAnalysisDepth, p.analysisDepthLimit())
+	} else if limit := p.analysisDepthLimit(); depth < 1 || depth > limit {
+		return p.errorResponse(fmt.Sprintf("depth must be between 1 and %d", limit), core.ErrInvalidRequest)
+	}
+
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+
+	b, err := board.ParseFEN(g.InitialFEN())
+	if err != nil {
+		return p.errorResponse("error parsing FEN", core.ErrInvalidFEN)
+	}
+
+	if !p.EngineAvailable() {
+		return p.errorResponse(errEngineUnavailable, core.ErrInvalidRequest)
+	}
+
+	// Positions already evaluated at this depth come from the cache
+	moves := g.Moves()
+	evals := make([]*engine.SearchResult, len(moves)+1)
+	keys := make([]string, len(evals))
+	var missing []int
+	for i, pos := 0, b; i < len(evals); i++ {
+		if i > 0 {
+			if pos, err = pos.ApplyMove(moves[i-1]); err != nil {
+				return p.errorResponse(fmt.Sprintf("error replaying move %d: %v", i, err), core.ErrInternalError)
+			}
+		}
+		keys[i] = depthKey(pos.ToFEN(), depth)
+		var ok bool
+		if evals[i], ok = p.cachedEval(keys[i]); !ok {
+			missing = append(missing, i)
+		}
+	}
+
+	if len(missing) > 0 {
+		// Depth searches have no time estimate, require budget left and charge what was spent
+		if !p.withinEngineBudget(cmd.GameID, cmd.ClientIP, 0) {
+			return p.errorResponse(errEngineBudget, core.ErrRateLimitExceeded)
+		}
+
+		select {
+		case p.analysisSlots <- struct{}{}:
+			defer func() { <-p.analysisSlots }()
+		default:
+			return p.errorResponse("analysis capacity reached, retry later", core.ErrResourceLimit)
+		}
+
+		eng, err := p.newAnalysisEngine()
+		if err != nil {
+			return p.errorResponse(fmt.Sprintf("failed to start engine: %v", err), core.ErrInternalError)
+		}
+		defer eng.Close()
+		eng.NewGame()
+
+		// Evaluate the start position and the positions after each move not cached
+		start := time.Now()
+		defer func() { p.chargeEngineTime(cmd.GameID, cmd.ClientIP, time.Since(start)) }()
+		for _, i := range missing {
+			eng.SetPosition(g.InitialFEN(), moves[:i])
+			evals[i], err = eng.SearchDepth(depth)
+			if err != nil {
+				return p.errorResponse(fmt.Sprintf("analysis failed at ply %d: %v", i, err), core.ErrInternalError)
+			}
+			p.storeEval(keys[i], evals[i])
+		}
+	}
+
+	// Engine scores are from the side to move, normalize to white's side
+	color := b.Turn()
+	whiteScore := func(i int) (int, int) {
+		score, mateIn := evals[i].Score, 0
+		if evals[i].IsMate {
+			mateIn = evals[i].MateIn
+			score = mateScore
+			if evals[i].Score < 0 {
+				score = -mateScore
+			}
+		}
+		// Side to move at ply i alternates from the initial turn
+		if (i%2 == 0) != (color == core.ColorWhite) {
+			return -score, -mateIn
+		}
+		return score, mateIn
+	}
+
+	plies := make([]core.PlyAnalysis, len(moves))
+	for i, move := range moves {
+		mover := color
+		if i%2 == 1 {
+			mover = core.OppositeColor(color)
+		}
+
+		before, _ := whiteScore(i)
+		after, mateIn := whiteScore(i + 1)
+		loss := clampEval(before) - clampEval(after)
+		if mover == core.ColorBlack {
+			loss = -loss
+		}
+
+		plies[i] = core.PlyAnalysis{
+			Ply:         i + 1,
+			Move:        move,
+			PlayerColor: mover.String(),
+			BestMove:    evals[i].BestMove,
+			Score:       after,
+			MateIn:      mateIn,
+			Loss:        max(loss, 0),
+			Tablebase:   evals[i+1].TBHits > 0,
+		}
+		if wdl := evals[i+1].WDL; wdl != nil {
+			// The opponent of the mover is to move after the ply
+			if mover == core.ColorWhite {
+				plies[i].WDL = []int{wdl[2], wdl[1], wdl[0]}
+			} else {
+				plies[i].WDL = wdl
+			}
+		}
+	}
 
 	return ProcessorResponse{
 		Success: true,
-		Data: core.BoardResponse{
-			FEN:   g.CurrentFEN(),
-			Board: ascii,
+		Data: core.AnalysisResponse{
+			GameID: cmd.GameID,
+			Depth:  depth,
+			Plies:  plies,
 		},
 	}
 }
 
+// handleGetHint suggests a move for the side to move without changing the game
+// Shares the analysis engine slots, a weaker skill level gives more human-like hints
+func (p *Processor) handleGetHint(cmd Command) ProcessorResponse {
+	strength, _ := cmd.Args.(int)
+	if strength < 0 || strength > maxHintStrength {
+		return p.errorResponse(fmt.Sprintf("strength must be between 0 and %d", maxHintStrength), core.ErrInvalidRequest)
+	}
+
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+	if g.State() != core.StateOngoing {
+		return p.errorResponse("game is not in progress", core.ErrGameOver)
+	}
+
+	if !p.EngineAvailable() {
+		return p.errorResponse(errEngineUnavailable, core.ErrInvalidRequest)
+	}
+
+	// Repeated hints for a position at the same strength reuse the cached search
+	key := timeKey(g.CurrentFEN(), hintSearchTime, strength)
+	search, ok := p.cachedEval(key)
+	if !ok {
+		if !p.withinEngineBudget(cmd.GameID, cmd.ClientIP, hintSearchTime*time.Millisecond) {
+			return p.errorResponse(errEngineBudget, core.ErrRateLimitExceeded)
+		}
+
+		select {
+		case p.analysisSlots <- struct{}{}:
+			defer func() { <-p.analysisSlots }()
+		default:
+			return p.errorResponse("analysis capacity reached, retry later", core.ErrResourceLimit)
+		}
+
+		eng, err := p.newAnalysisEngine()
+		if err != nil {
+			return p.errorResponse(fmt.Sprintf("failed to start engine: %v", err), core.ErrInternalError)
+		}
+		defer eng.Close()
+
+		eng.SetSkillLevel(strength)
+		eng.SetPosition(g.CurrentFEN(), []string{})
+		start := time.Now()
+		search, err = eng.Search(hintSearchTime)
+		p.chargeEngineTime(cmd.GameID, cmd.ClientIP, time.Since(start))
+		if err != nil {
+			return p.errorResponse(fmt.Sprintf("hint search failed: %v", err), core.ErrInternalError)
+		}
+		p.storeEval(key, search)
+	}
+	if search.BestMove == "" || search.BestMove == "(none)" {
+		return p.errorResponse("no legal moves", core.ErrGameOver)
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Data: core.MoveInfo{
+			Move:        search.BestMove,
+			PlayerColor: g.NextTurnColor().String(),
+			Score:       search.Score,
+			Depth:       search.Depth,
+		},
+	}
+}
+
+// handleGradeMove evaluates the position before and after a candidate move at
+// the analysis depth and grades the move by what it gives up against the best move
+func (p *Processor) handleGradeMove(cmd Command) ProcessorResponse {
+	req, ok := cmd.Args.(core.GradeRequest)
+	if !ok {
+		return p.errorResponse("invalid request arguments", core.ErrInternalError)
+	}
+	move := strings.ToLower(strings.TrimSpace(req.Move))
+
+	g, err := p.svc.GetGame(cmd.GameID)
+	if err != nil {
+		return p.errorResponse("game not found", core.ErrGameNotFound)
+	}
+	if g.State() != core.StateOngoing {
+		return p.errorResponse("game is not in progress", core.ErrGameOver)
+	}
+
+	// The candidate is never played, the native board validates it without the engine
+	before, err := board.ParseFEN(g.CurrentFEN())
+	if err != nil {
+		return p.errorResponse("error parsing FEN", core.ErrInvalidFEN)
+	}
+	after, err := before.ApplyMove(move)
+	if err != nil {
+		return p.errorResponse("illegal move", core.ErrInvalidMove)
+	}
+
+	if !p.EngineAvailable() {
+		return p.errorResponse(errEngineUnavailable, core.ErrInvalidRequest)
+	}
+
+	depth := min(defaultAnalysisDepth, p.analysisDepthLimit())
+	fens := []string{before.ToFEN(), after.ToFEN()}
+	evals := make([]*engine.SearchResult, len(fens))
+	var missing []int
+	for i, fen := range fens {
+		if evals[i], ok = p.cachedEval(depthKey(fen, depth)); !ok {
+			missing = append(missing, i)
+		}
+	}
+
+	if len(missing) > 0 {
+		if !p.withinEngineBudget(cmd.GameID, cmd.ClientIP, 0) {
+			return p.errorResponse(errEngineBudget, core.ErrRateLimitExceeded)
+		}
+
+		select {
+		case p.analysisSlots <- struct{}{}:
+			defer func() { <-p.analysisSlots }()
+		default:
+			return p.errorResponse("analysis capacity reached, retry later", core.ErrResourceLimit)
+		}
+
+		eng, err := p.newAnalysisEngine()
+		if err != nil {
+			return p.errorResponse(fmt.Sprintf("failed to start engine: %v", err), core.ErrInternalError)
+		}
+		defer eng.Close()
+		eng.NewGame()
+
+		start := time.Now()
+		defer func() { p.chargeEngineTime(cmd.GameID, cmd.ClientIP, time.Since(start)) }()
+		for _, i := range missing {
+			eng.SetPosition(fens[i], []string{})
+			if evals[i], err = eng.SearchDepth(depth); err != nil {
+				return p.errorResponse(fmt.Sprintf("grading failed: %v", err), core.ErrInternalError)
+			}
+			p.storeEval(depthKey(fens[i], depth), evals[i])
+		}
+	}
+
+	// The position after the move is evaluated for the opponent
+	bestScore := clampEval(moverScore(evals[0]))
+	score := -clampEval(moverScore(evals[1]))
+	switch {
+	case after.IsCheckmate():
+		score = analysisEvalCap
+	case after.IsStalemate():
+		score = 0
+	}
+
+	loss := max(bestScore-score, 0)
+	if move == evals[0].BestMove {
+		loss = 0 // Depth noise between the two searches is not a loss
+	}
+
+	return ProcessorResponse{
+		Success: true,
+		Data: core.GradeResponse{
+			Move:      move,
+			BestMove:  evals[0].BestMove,
+			Score:     score,
+			BestScore: bestScore,
+			Loss:      loss,
+			Grade:     gradeMove(move == evals[0].BestMove, loss),
+			Depth:     depth,
+		},
+	}
+}
+
+// moverScore returns an evaluation from the side to move with mates as mateScore
+func moverScore(r *engine.SearchResult) int {
+	if !r.IsMate {
+		return r.Score
+	}
+	if r.Score < 0 {
+		return -mateScore
+	}
+	return mateScore
+}
+
+func gradeMove(best bool, loss int) string {
+	switch {
+	case best:
+		return core.GradeBest
+	case loss >= blunderLoss:
+		return core.GradeBlunder
+	case loss >= mistakeLoss:
+		return core.GradeMistake
+	case loss >= inaccuracyLoss:
+		return core.GradeInaccuracy
+	default:
+		return core.GradeGood
+	}
+}
+
+func clampEval(score int) int {
+	return min(max(score, -analysisEvalCap), analysisEvalCap)
+}
+
 // triggerComputerMove initiates async engine calculation
-func (p *Processor) triggerComputerMove(gameID string, g *game.Game) {
+// The search time is charged to the game and the requesting client's engine budget
+// The returned channel is closed once the result has been applied to the game
+func (p *Processor) triggerComputerMove(gameID string, g *game.Game, clientIP string) <-chan struct{} {
 	fen := g.CurrentFEN()
 	color := g.NextTurnColor()
 	player := g.NextPlayer()
+	done := make(chan struct{})
 
 	// Submit to queue with callback and computer config
-	p.queue.SubmitAsync(gameID, fen, color, player, func(result EngineResult) {
+	p.searchStreams.begin(gameID)
+	err := p.queue.SubmitAsync(gameID, fen, color, player, func(result EngineResult) {
+		defer close(done)
+		p.searchStreams.end(gameID, result.Move, result.Error)
+		p.chargeEngineTime(gameID, clientIP, result.Elapsed)
+
 		// Check if game still exists
 		currentGame, err := p.svc.GetGame(gameID)
 		if err != nil {
@@ -500,7 +1674,7 @@ func (p *Processor) triggerComputerMove(gameID string, g *game.Game) {
 		}
 
 		// Use centralized state determination
-		state := p.determineGameEndState(core.OppositeColor(color), &engine.SearchResult{
+		state := p.determineGameEndState(fen, core.OppositeColor(color), &engine.SearchResult{
 			BestMove: result.Move,
 			Score:    result.Score,
 			Depth:    result.Depth,
@@ -509,17 +1683,19 @@ func (p *Processor) triggerComputerMove(gameID string, g *game.Game) {
 		})
 
 		if state != core.StateOngoing {
-			p.svc.UpdateGameState(gameID, state)
+			p.endGame(gameID, state)
 			return
 		}
 
 		// Apply computer move
-		p.mu.Lock()
-		p.validationEng.SetPosition(fen, []string{result.Move})
-		newFEN, _ := p.validationEng.GetFEN()
-		p.mu.Unlock()
+		newFEN, err := p.computerPositionAfter(fen, result.Move)
+		if err != nil {
+			log.Printf("Failed to apply computer move %s for game %s: %v", result.Move, gameID, err)
+			p.svc.UpdateGameState(gameID, core.StateStuck)
+			return
+		}
 
-		p.svc.ApplyMove(gameID, result.Move, newFEN)
+		p.svc.ApplyMove(gameID, result.Move, newFEN, -1)
 		p.svc.SetLastMoveResult(gameID, &game.MoveResult{
 			Move:        result.Move,
 			PlayerColor: color,
@@ -532,14 +1708,81 @@ func (p *Processor) triggerComputerMove(gameID string, g *game.Game) {
 
 		// Check if opponent is checkmated
 		p.checkGameEnd(gameID, newFEN, color)
+		p.checkDrawRules(gameID)
+		p.checkDeadPosition(gameID, newFEN)
 	})
+	if err != nil {
+		p.searchStreams.end(gameID, "", err)
+		close(done)
+	}
+	return done
+}
+
+// checkDrawRules draws an ongoing game whose position occurred a third time or
+// whose halfmove clock reached fiftyMovePlies
+// Undo truncates the snapshots both come from and reopens the game, so taking
+// back the deciding move undoes the draw
+func (p *Processor) checkDrawRules(gameID string) {
+	g, err := p.svc.GetGame(gameID)
+	if err != nil || g.State() != core.StateOngoing {
+		return
+	}
+
+	reason := ""
+	if g.RepetitionCount() >= 3 {
+		reason = core.ReasonRepetition
+	} else if b, err := board.ParseFEN(g.CurrentFEN()); err == nil && b.HalfMove() >= fiftyMovePlies {
+		reason = core.ReasonFiftyMove
+	}
+	if reason == "" {
+		return
+	}
+
+	p.svc.EndGame(gameID, core.StateDraw, reason)
+	if p.queue != nil {
+		p.queue.Forget(gameID)
+	}
+}
+
+// checkDeadPosition ends an ongoing computer versus computer game as a draw
+// once SetDeadPositionDraw deems it unwinnable
+func (p *Processor) checkDeadPosition(gameID, fen string) {
+	if p.deadPlies <= 0 {
+		return
+	}
+	g, err := p.svc.GetGame(gameID)
+	if err != nil || g.State() != core.StateOngoing {
+		return
+	}
+	if g.GetPlayer(core.ColorWhite).Type != core.PlayerComputer || g.GetPlayer(core.ColorBlack).Type != core.PlayerComputer {
+		return
+	}
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return
+	}
+
+	if b.IsInsufficientMaterial() || (g.LevelPlies() >= p.deadPlies && b.HalfMove() >= p.deadHalfmove) {
+		log.Printf("Drawing dead position in game %s after %d level evaluations, halfmove clock %d",
+			gameID, g.LevelPlies(), b.HalfMove())
+		p.svc.EndGame(gameID, core.StateDraw, core.ReasonDeadPosition)
+		if p.queue != nil {
+			p.queue.Forget(gameID)
+		}
+	}
 }
 
 // determineGameEndState centralized function to determine game end state based on engine evaluation
-func (p *Processor) determineGameEndState(lastMoveBy core.Color, searchResult *engine.SearchResult) core.State {
+// A "(none)" best move only tells that fen has no legal moves, the mate flag of the
+// preceding info line is unreliable then, so the native board decides checkmate vs stalemate
+func (p *Processor) determineGameEndState(fen string, lastMoveBy core.Color, searchResult *engine.SearchResult) core.State {
 	// No legal moves detected
 	if searchResult.BestMove == "" || searchResult.BestMove == "(none)" {
-		if searchResult.IsMate {
+		inCheck := searchResult.IsMate
+		if b, err := board.ParseFEN(fen); err == nil {
+			inCheck = b.InCheck()
+		}
+		if inCheck {
 			// It's a checkmate - the side that just moved wins
 			if lastMoveBy == core.ColorWhite {
 				return core.StateWhiteWins
@@ -556,30 +1799,243 @@ func (p *Processor) determineGameEndState(lastMoveBy core.Color, searchResult *e
 
 // checkGameEnd determines if game has ended
 func (p *Processor) checkGameEnd(gameID, fen string, lastMoveBy core.Color) {
+	if !p.EngineAvailable() {
+		p.checkGameEndNative(gameID, fen)
+		return
+	}
+
+	key := timeKey(fen, gameEndSearchTime, fullSkillLevel)
+	search, ok := p.cachedEval(key)
+	if !ok {
+		var err error
+		p.mu.Lock()
+		search, err = p.validationSearch(fen, gameEndSearchTime)
+		p.mu.Unlock()
+		if err != nil {
+			log.Printf("Engine error checking game end for %s: %v", gameID, err)
+			p.checkGameEndNative(gameID, fen)
+			return
+		}
+		p.storeEval(key, search)
+	}
+
+	// Use centralized state determination
+	if state := p.determineGameEndState(fen, lastMoveBy, search); state != core.StateOngoing {
+		p.endGame(gameID, state)
+	}
+}
+
+// endGame records a state from determineGameEndState with its reason
+func (p *Processor) endGame(gameID string, state core.State) {
+	reason := core.ReasonCheckmate
+	if state == core.StateStalemate {
+		reason = core.ReasonStalemate
+	}
+	p.svc.EndGame(gameID, state, reason)
+	if p.queue != nil {
+		p.queue.Forget(gameID)
+	}
+}
+
+// checkGameEndNative detects checkmate and stalemate with the native board
+func (p *Processor) checkGameEndNative(gameID, fen string) {
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case b.IsCheckmate():
+		state := core.StateWhiteWins
+		if b.Turn() == core.ColorWhite {
+			state = core.StateBlackWins
+		}
+		p.svc.EndGame(gameID, state, core.ReasonCheckmate)
+	case b.IsStalemate():
+		p.svc.EndGame(gameID, core.StateStalemate, core.ReasonStalemate)
+	}
+}
+
+// canonicalFEN normalizes a FEN through the engine, or the native board without one
+func (p *Processor) canonicalFEN(fen string) (string, error) {
+	if !p.EngineAvailable() {
+		return nativeCanonicalFEN(fen)
+	}
+
 	p.mu.Lock()
-	p.validationEng.SetPosition(fen, []string{})
-	search, _ := p.validationEng.Search(100)
+	p.validationEng.NewGame()
+	canonical, err := p.validationFEN(fen, []string{})
 	p.mu.Unlock()
 
-	// Use centralized state determination
-	state := p.determineGameEndState(lastMoveBy, search)
-	if state != core.StateOngoing {
-		p.svc.UpdateGameState(gameID, state)
+	if errors.Is(err, engine.ErrTimeout) && p.fenFallback {
+		log.Printf("Engine timed out reporting FEN, normalizing natively: %v", err)
+		return nativeCanonicalFEN(fen)
+	}
+	return canonical, err
+}
+
+// positionAfter validates a move and returns the resulting FEN, an
+// unchanged FEN from the engine means the move was rejected
+// The position is deterministic, so an engine timeout falls back to the native board
+func (p *Processor) positionAfter(fen, move string) (string, error) {
+	if !p.EngineAvailable() {
+		return nativePositionAfter(fen, move)
+	}
+
+	p.mu.Lock()
+	newFEN, err := p.validationFEN(fen, []string{move})
+	p.mu.Unlock()
+
+	if errors.Is(err, engine.ErrTimeout) && p.fenFallback {
+		log.Printf("Engine timed out reporting FEN, applying %s natively: %v", move, err)
+		return nativePositionAfter(fen, move)
 	}
+	return newFEN, err
 }
 
-// buildGameResponse constructs standard game response
-func (p *Processor) buildGameResponse(gameID string, g *game.Game) core.GameResponse {
+// validationFEN reads the FEN after moves from the validation engine, retrying
+// failed replies since a freshly reset engine occasionally answers empty
+// Caller must hold mu
+func (p *Processor) validationFEN(fen string, moves []string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		p.validationEng.SetPosition(fen, moves)
+		result, err := p.validationEng.GetFEN()
+		if err == nil || errors.Is(err, engine.ErrTimeout) || attempt > p.retries {
+			return result, err
+		}
+		log.Printf("Validation engine FEN failed, retrying (%d/%d): %v", attempt, p.retries, err)
+		if err := p.validationEng.Ping(); err != nil {
+			return "", err
+		}
+	}
+}
+
+// validationSearch searches fen on the validation engine, retrying failed
+// replies and replies without a best move line
+// Caller must hold mu
+func (p *Processor) validationSearch(fen string, timeMs int) (*engine.SearchResult, error) {
+	for attempt := 1; ; attempt++ {
+		p.validationEng.SetPosition(fen, []string{})
+		result, err := p.validationEng.Search(timeMs)
+		if err == nil && result.BestMove == "" {
+			err = fmt.Errorf("engine returned no best move")
+		}
+		if err == nil || errors.Is(err, engine.ErrTimeout) || attempt > p.retries {
+			return result, err
+		}
+		log.Printf("Validation engine search failed, retrying (%d/%d): %v", attempt, p.retries, err)
+		if err := p.validationEng.Ping(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// computerPositionAfter applies a move found by an engine search, the move is
+// trusted so the native board computes the FEN without taking mu
+// A move the native board rejects is validated by the engine instead
+func (p *Processor) computerPositionAfter(fen, move string) (string, error) {
+	if p.nativeApply {
+		next, err := nativePositionAfter(fen, move)
+		if err == nil {
+			return next, nil
+		}
+		log.Printf("Native board rejected engine move %s, validating with engine: %v", move, err)
+	}
+	return p.positionAfter(fen, move)
+}
+
+func nativeCanonicalFEN(fen string) (string, error) {
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return "", err
+	}
+	return b.ToFEN(), nil
+}
+
+// checkNormalizedFEN verifies the normalized board still has the side to move
+// of the requested FEN and describes a position that could occur in a game
+func checkNormalizedFEN(requested string, normalized *board.Board) error {
+	b, err := board.ParseFEN(requested)
+	if err != nil {
+		return err
+	}
+	if b.Turn() != normalized.Turn() {
+		return fmt.Errorf("side to move is %s but the engine normalized it to %s", b.Turn().Name(), normalized.Turn().Name())
+	}
+	return normalized.ValidatePosition()
+}
+
+func nativePositionAfter(fen, move string) (string, error) {
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return "", err
+	}
+	next, err := b.ApplyMove(move)
+	if err != nil {
+		return "", err
+	}
+	return next.ToFEN(), nil
+}
+
+// checkInitialPosition ends a game created from a finished position, using the
+// native board so draws by insufficient material are recognized as well
+func (p *Processor) checkInitialPosition(gameID, fen string) {
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return
+	}
+
+	switch reason := finishedReason(b); reason {
+	case core.ReasonCheckmate:
+		// The side to move is mated, so the other side delivered it
+		state := core.StateWhiteWins
+		if b.Turn() == core.ColorWhite {
+			state = core.StateBlackWins
+		}
+		p.svc.EndGame(gameID, state, reason)
+	case core.ReasonStalemate:
+		p.svc.EndGame(gameID, core.StateStalemate, reason)
+	case core.ReasonInsufficientMaterial:
+		p.svc.EndGame(gameID, core.StateDraw, reason)
+	}
+}
+
+// finishedReason returns why a position is already decided, empty if play can continue
+func finishedReason(b *board.Board) string {
+	switch {
+	case b.IsCheckmate():
+		return core.ReasonCheckmate
+	case b.IsStalemate():
+		return core.ReasonStalemate
+	case b.IsInsufficientMaterial():
+		return core.ReasonInsufficientMaterial
+	}
+	return ""
+}
+
+// buildGameResponse constructs standard game response, with the moves inside window
+func (p *Processor) buildGameResponse(gameID string, g *game.Game, window core.MoveWindow) core.GameResponse {
+	moves := g.Moves()
+	from := min(window.From, len(moves))
+	to := len(moves)
+	if window.Limit > 0 {
+		to = min(from+window.Limit, to)
+	}
+
 	resp := core.GameResponse{
-		GameID: gameID,
-		FEN:    g.CurrentFEN(),
-		Turn:   g.NextTurnColor().String(),
-		State:  g.State().String(),
-		Moves:  g.Moves(),
+		GameID:     gameID,
+		FEN:        g.CurrentFEN(),
+		InitialFEN: g.InitialFEN(),
+		Turn:       g.NextTurnColor().String(),
+		State:      g.State().String(),
+		Reason:     g.Reason(),
+		Moves:      moves[from:to],
+		MoveCount:  len(moves),
 		Players: core.PlayersResponse{
 			White: g.GetPlayer(core.ColorWhite),
 			Black: g.GetPlayer(core.ColorBlack),
 		},
+		PreviousGameID: g.PreviousGameID(),
 	}
 
 	// Include last move if available
@@ -608,6 +2064,9 @@ func (p *Processor) errorResponse(message, code string) ProcessorResponse {
 
 // Close cleans up resources
 func (p *Processor) Close() error {
+	if !p.EngineAvailable() {
+		return nil
+	}
 	p.queue.Shutdown(5 * time.Second)
 	return p.validationEng.Close()
 }
\ No newline at end of file
@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"chess/internal/server/board"
+	"chess/internal/server/core"
+	"chess/internal/server/engine/enginetest"
+	"chess/internal/server/service"
+)
+
+// newTestProcessor returns a processor whose engines play script, closed when the test ends
+func newTestProcessor(t *testing.T, script ...string) *Processor {
+	t.Helper()
+	svc, err := service.New(service.Options{})
+	if err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	p, err := NewWithEngine(svc, 1, enginetest.Factory(script...))
+	if err != nil {
+		t.Fatalf("processor: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+// execute runs cmd and fails the test unless it succeeded with a game response
+func execute(t *testing.T, p *Processor, cmd Command) core.GameResponse {
+	t.Helper()
+	resp := p.Execute(cmd)
+	if !resp.Success {
+		t.Fatalf("command %d failed: %+v", cmd.Type, resp.Error)
+	}
+	game, ok := resp.Data.(core.GameResponse)
+	if !ok {
+		t.Fatalf("command %d returned %T, want core.GameResponse", cmd.Type, resp.Data)
+	}
+	return game
+}
+
+func TestComputerPlaysScriptedOpening(t *testing.T) {
+	p := newTestProcessor(t, "e2e4")
+
+	game := execute(t, p, NewCreateGameCommand(core.CreateGameRequest{
+		White:         core.PlayerConfig{Type: core.PlayerComputer, SearchTime: 100},
+		Black:         core.PlayerConfig{Type: core.PlayerHuman},
+		AutoStart:     true,
+		WaitFirstMove: true,
+	}))
+	if game.State != core.StateOngoing.String() {
+		t.Fatalf("state = %s, want ongoing", game.State)
+	}
+	if len(game.Moves) != 1 || game.Moves[0] != "e2e4" {
+		t.Fatalf("moves = %v, want [e2e4]", game.Moves)
+	}
+}
+
+func TestComputerMateRecordsWinnerAndReason(t *testing.T) {
+	// After 1. f3 e5 2. g4 black mates with Qh4
+	p := newTestProcessor(t, "d8h4")
+
+	game := execute(t, p, NewCreateGameCommand(core.CreateGameRequest{
+		White:         core.PlayerConfig{Type: core.PlayerHuman},
+		Black:         core.PlayerConfig{Type: core.PlayerComputer, SearchTime: 100},
+		FEN:           "rnbqkbnr/pppp1ppp/8/4p3/6P1/5P2/PPPPP2P/RNBQKBNR b KQkq - 0 2",
+		AutoStart:     true,
+		WaitFirstMove: true,
+	}))
+	if game.State != core.StateBlackWins.String() || game.Reason != core.ReasonCheckmate {
+		t.Fatalf("state = %s (%s), want %s (%s)", game.State, game.Reason, core.StateBlackWins, core.ReasonCheckmate)
+	}
+}
+
+func TestHumanMoveRejectedWhileComputerToMove(t *testing.T) {
+	p := newTestProcessor(t)
+
+	game := execute(t, p, NewCreateGameCommand(core.CreateGameRequest{
+		White: core.PlayerConfig{Type: core.PlayerComputer, SearchTime: 100},
+		Black: core.PlayerConfig{Type: core.PlayerHuman},
+	}))
+
+	resp := p.Execute(NewMakeMoveCommand(game.GameID, core.MoveRequest{Move: "e2e4"}))
+	if resp.Success {
+		t.Fatal("human move for the computer's side was accepted")
+	}
+}
+
+func TestCheckNormalizedFENRejectsFlippedTurn(t *testing.T) {
+	requested := "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1"
+	flipped, err := board.ParseFEN("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 0 1")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	err = checkNormalizedFEN(requested, flipped)
+	if err == nil || !strings.Contains(err.Error(), "side to move is black") {
+		t.Fatalf("checkNormalizedFEN() = %v, want a side to move error", err)
+	}
+
+	same, _ := board.ParseFEN(requested)
+	if err := checkNormalizedFEN(requested, same); err != nil {
+		t.Fatalf("checkNormalizedFEN() with matching turn = %v", err)
+	}
+}
+
+func TestSaturatedQueueRefusesComputerGames(t *testing.T) {
+	p := newTestProcessor(t)
+	p.SetQueueReject(1)
+	p.queue.queued.Store(2)
+
+	resp := p.Execute(NewCreateGameCommand(core.CreateGameRequest{
+		White: core.PlayerConfig{Type: core.PlayerHuman},
+		Black: core.PlayerConfig{Type: core.PlayerComputer, SearchTime: 100},
+	}))
+	if resp.Success || resp.Error.Code != core.ErrEngineSaturated {
+		t.Fatalf("create = %+v, want %s", resp.Error, core.ErrEngineSaturated)
+	}
+
+	execute(t, p, NewCreateGameCommand(core.CreateGameRequest{
+		White: core.PlayerConfig{Type: core.PlayerHuman},
+		Black: core.PlayerConfig{Type: core.PlayerHuman},
+	}))
+}
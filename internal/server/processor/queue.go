@@ -3,7 +3,9 @@ package processor
 import (
 	"context"
 	"fmt"
+	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"chess/internal/server/core"
@@ -21,26 +23,60 @@ type EngineTask struct {
 
 // EngineResult contains the outcome of an engine calculation
 type EngineResult struct {
-	GameID string
-	Move   string
-	Score  int
-	Depth  int
-	IsMate bool
-	MateIn int
-	Error  error
+	GameID  string
+	Move    string
+	Score   int
+	Depth   int
+	IsMate  bool
+	MateIn  int
+	Elapsed time.Duration // Wall time of the search, charged to the engine budget
+	Error   error
 }
 
 // EngineQueue manages async engine computations
 type EngineQueue struct {
-	tasks   chan EngineTask
-	workers int
-	wg      sync.WaitGroup
-	ctx     context.Context
-	cancel  context.CancelFunc
+	tasks      chan EngineTask
+	workers    int
+	wg         sync.WaitGroup
+	ctx        context.Context
+	cancel     context.CancelFunc
+	newEngine  engine.Factory
+	syzygyPath string        // Set before tasks are submitted, workers apply it on their first task
+	maxDepth   int           // Search depth ceiling, set before tasks are submitted
+	warming    atomic.Int32  // Workers still starting their engine
+	warm       atomic.Int32  // Workers whose engine answered isready
+	warmed     chan struct{} // Closed once every worker finished starting
+	started    time.Time
+	affinity   bool              // Prefer the worker that searched the game's previous move
+	preferred  []chan EngineTask // Per worker, unbuffered so a send only succeeds when the worker is idle
+	lastWorker map[string]int    // Game ID to the worker that searched its last move
+	affinityMu sync.Mutex
+	onInfo     func(gameID string, progress engine.SearchResult) // Search progress listener, set before tasks are submitted
+	busy       atomic.Int32                                      // Workers processing a task
+	queued     atomic.Int32                                      // Tasks waiting for a worker
+	threshold  int                                               // Queued tasks that count as saturated, zero disables, set before tasks are submitted
+	sustain    time.Duration
+	overSince  atomic.Int64 // Unix nanoseconds since the queue is above threshold, zero when below
 }
 
-// NewEngineQueue creates a queue with specified worker count
-func NewEngineQueue(workerCount int) *EngineQueue {
+// QueueStats reports engine pool load for /health
+type QueueStats struct {
+	Workers  int  `json:"workers"`
+	Busy     int  `json:"busy"`
+	Queued   int  `json:"queued"`
+	Degraded bool `json:"degraded"` // Queued above the threshold for the sustain period
+}
+
+// DefaultQueueSustain is how long the queue stays above its saturation
+// threshold before it is reported degraded
+const DefaultQueueSustain = 30 * time.Second
+
+// maxAffinityGames bounds the worker hints kept, games beyond it use any worker
+const maxAffinityGames = 10000
+
+// NewEngineQueue creates a queue with specified worker count, each worker
+// starting its own engine from factory
+func NewEngineQueue(workerCount int, factory engine.Factory) *EngineQueue {
 	if workerCount < 1 {
 		workerCount = 2 // Default
 	}
@@ -48,11 +84,20 @@ func NewEngineQueue(workerCount int) *EngineQueue {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	q := &EngineQueue{
-		tasks:   make(chan EngineTask, 100), // Buffered for queueing
-		workers: workerCount,
-		ctx:     ctx,
-		cancel:  cancel,
+		tasks:      make(chan EngineTask, 100), // Buffered for queueing
+		workers:    workerCount,
+		newEngine:  factory,
+		ctx:        ctx,
+		cancel:     cancel,
+		warmed:     make(chan struct{}),
+		started:    time.Now(),
+		preferred:  make([]chan EngineTask, workerCount),
+		lastWorker: make(map[string]int),
+	}
+	for i := range q.preferred {
+		q.preferred[i] = make(chan EngineTask)
 	}
+	q.warming.Store(int32(workerCount))
 
 	q.start()
 	return q
@@ -70,38 +115,128 @@ func (q *EngineQueue) start() {
 func (q *EngineQueue) worker(id int) {
 	defer q.wg.Done()
 
-	// Each worker gets its own engine instance
-	eng, err := engine.New()
+	// Each worker gets its own engine instance, ready for a game before the first task
+	eng, err := q.newEngine()
 	if err != nil {
 		fmt.Printf("Worker %d failed to initialize engine: %v\n", id, err)
+		q.workerStarted(false)
 		return
 	}
 	defer eng.Close()
+	eng.NewGame()
+	q.workerStarted(eng.Ping() == nil)
+
+	// Options set for earlier players, restored to defaults before the next task
+	applied := make(map[string]bool)
+	tablebases := false
 
 	for {
+		var task EngineTask
 		select {
-		case task, ok := <-q.tasks:
+		case task = <-q.preferred[id]:
+		case queued, ok := <-q.tasks:
 			if !ok {
 				return // Channel closed
 			}
+			task = queued
+			q.trackSaturation(q.queued.Add(-1))
+		case <-q.ctx.Done():
+			return
+		}
 
-			result := q.processTask(eng, task)
+		if !tablebases && q.syzygyPath != "" {
+			eng.SetSyzygyPath(q.syzygyPath)
+			tablebases = true
+		}
+		eng.SetMaxDepth(q.maxDepth)
+		q.remember(task.GameID, id)
 
-			// Send result if receiver still listening
-			select {
-			case task.Response <- result:
-			case <-time.After(15 * time.Millisecond):
-				// Receiver abandoned, discard result
-			}
+		q.busy.Add(1)
+		result := q.processTask(eng, task, applied)
+		q.busy.Add(-1)
 
-		case <-q.ctx.Done():
-			return
+		// Send result if receiver still listening
+		select {
+		case task.Response <- result:
+		case <-time.After(15 * time.Millisecond):
+			// Receiver abandoned, discard result
 		}
 	}
 }
 
+// remember records the worker searching gameID when affinity is enabled
+func (q *EngineQueue) remember(gameID string, worker int) {
+	if !q.affinity {
+		return
+	}
+	q.affinityMu.Lock()
+	defer q.affinityMu.Unlock()
+	if _, ok := q.lastWorker[gameID]; ok || len(q.lastWorker) < maxAffinityGames {
+		q.lastWorker[gameID] = worker
+	}
+}
+
+// Forget drops the worker hint of a game that ended or was deleted
+func (q *EngineQueue) Forget(gameID string) {
+	q.affinityMu.Lock()
+	delete(q.lastWorker, gameID)
+	q.affinityMu.Unlock()
+}
+
+// submitPreferred hands task to the worker that searched the game's last
+// move, reusing its hash tables, and reports false when that worker is busy
+func (q *EngineQueue) submitPreferred(task EngineTask) bool {
+	if !q.affinity {
+		return false
+	}
+	q.affinityMu.Lock()
+	worker, ok := q.lastWorker[task.GameID]
+	q.affinityMu.Unlock()
+	if !ok {
+		return false
+	}
+
+	select {
+	case q.preferred[worker] <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// workerStarted records a worker's warmup outcome
+func (q *EngineQueue) workerStarted(ready bool) {
+	if ready {
+		q.warm.Add(1)
+	}
+	if q.warming.Add(-1) == 0 {
+		close(q.warmed)
+	}
+}
+
+// Warm reports whether every worker finished starting its engine
+func (q *EngineQueue) Warm() bool {
+	return q.warming.Load() == 0
+}
+
+// ReadyWorkers returns the number of workers with a responsive engine
+func (q *EngineQueue) ReadyWorkers() int {
+	return int(q.warm.Load())
+}
+
+// WaitWarm blocks until every worker finished starting or timeout passes,
+// returning the time since the queue was created
+func (q *EngineQueue) WaitWarm(timeout time.Duration) (time.Duration, bool) {
+	select {
+	case <-q.warmed:
+		return time.Since(q.started), true
+	case <-time.After(timeout):
+		return time.Since(q.started), false
+	}
+}
+
 // processTask executes a single engine calculation
-func (q *EngineQueue) processTask(eng *engine.UCI, task EngineTask) EngineResult {
+func (q *EngineQueue) processTask(eng engine.Engine, task EngineTask, applied map[string]bool) EngineResult {
 	result := EngineResult{
 		GameID: task.GameID,
 	}
@@ -109,19 +244,20 @@ func (q *EngineQueue) processTask(eng *engine.UCI, task EngineTask) EngineResult
 	// Apply computer configuration if provided
 	if task.Player.Type == core.PlayerComputer {
 		eng.SetSkillLevel(task.Player.Level)
+		applyEngineOptions(eng, task.Player.EngineOptions, applied)
 	}
 
 	// Setup position
 	eng.SetPosition(task.FEN, []string{})
 
-	// Determine search time
-	searchTime := 1000 // Default 1 second
-	if task.Player.Type == core.PlayerComputer && task.Player.SearchTime > 0 {
-		searchTime = task.Player.SearchTime
-	}
-
 	// Search for best move
-	search, err := eng.Search(searchTime)
+	start := time.Now()
+	var onInfo func(engine.SearchResult)
+	if q.onInfo != nil {
+		onInfo = func(progress engine.SearchResult) { q.onInfo(task.GameID, progress) }
+	}
+	search, err := eng.SearchWithProgress(searchTime(task.Player), onInfo)
+	result.Elapsed = time.Since(start)
 	if err != nil {
 		result.Error = fmt.Errorf("engine search failed: %v", err)
 		return result
@@ -144,19 +280,90 @@ func (q *EngineQueue) processTask(eng *engine.UCI, task EngineTask) EngineResult
 	return result
 }
 
+// applyEngineOptions resets options left by earlier players, then sets the player's own
+func applyEngineOptions(eng engine.Engine, options map[string]string, applied map[string]bool) {
+	for name := range applied {
+		if _, ok := options[name]; ok {
+			continue
+		}
+		if value, ok := eng.OptionDefault(name); ok {
+			eng.SetOption(name, value)
+		}
+		delete(applied, name)
+	}
+	for name, value := range options {
+		if err := eng.SetOption(name, value); err != nil {
+			log.Printf("Skipping engine option: %v", err)
+			continue
+		}
+		applied[name] = true
+	}
+}
+
+// searchTime returns the engine time budget for a player in milliseconds,
+// after any handicap so the live setting applies to every search
+func searchTime(player *core.Player) int {
+	if player.Type == core.PlayerComputer && player.SearchTime > 0 {
+		return player.HandicappedSearchTime()
+	}
+	return 1000 // Default 1 second
+}
+
+// searchDuration is the time a search for player is expected to take
+func searchDuration(player *core.Player) time.Duration {
+	return time.Duration(searchTime(player)) * time.Millisecond
+}
+
 // Submit adds a task to the queue
 func (q *EngineQueue) Submit(task EngineTask) error {
+	// Counted before the send so a worker taking the task never sees it negative
+	q.trackSaturation(q.queued.Add(1))
 	select {
 	case q.tasks <- task:
 		return nil
 	case <-q.ctx.Done():
+		q.trackSaturation(q.queued.Add(-1))
 		return fmt.Errorf("queue is shutting down")
 	default:
+		q.trackSaturation(q.queued.Add(-1))
 		return fmt.Errorf("queue is full")
 	}
 }
 
+// SetSaturation marks the queue degraded once more than threshold tasks
+// stay queued for sustain, zero threshold disables
+func (q *EngineQueue) SetSaturation(threshold int, sustain time.Duration) {
+	q.threshold = threshold
+	q.sustain = sustain
+}
+
+// trackSaturation records when the queue length crossed the threshold
+func (q *EngineQueue) trackSaturation(queued int32) {
+	if q.threshold <= 0 {
+		return
+	}
+	if int(queued) > q.threshold {
+		q.overSince.CompareAndSwap(0, time.Now().UnixNano())
+	} else {
+		q.overSince.Store(0)
+	}
+}
+
+// Stats returns the current worker and queue load
+func (q *EngineQueue) Stats() QueueStats {
+	stats := QueueStats{
+		Workers: q.workers,
+		Busy:    int(q.busy.Load()),
+		Queued:  int(q.queued.Load()),
+	}
+	if since := q.overSince.Load(); since != 0 {
+		stats.Degraded = time.Since(time.Unix(0, since)) >= q.sustain
+	}
+	return stats
+}
+
 // SubmitAsync submits a task without blocking for result
+// With affinity the game's previous worker takes it if idle, otherwise any worker
 func (q *EngineQueue) SubmitAsync(gameID, fen string, color core.Color, player *core.Player, callback func(EngineResult)) error {
 	respChan := make(chan EngineResult, 1)
 
@@ -168,16 +375,21 @@ func (q *EngineQueue) SubmitAsync(gameID, fen string, color core.Color, player *
 		Response: respChan,
 	}
 
-	if err := q.Submit(task); err != nil {
-		return err
+	if !q.submitPreferred(task) {
+		if err := q.Submit(task); err != nil {
+			return err
+		}
 	}
 
+	// Engine search gives up at twice the search time, allow that plus queueing
+	timeout := time.Duration(searchTime(player)*2)*time.Millisecond + 5*time.Second
+
 	// Handle result in background
 	go func() {
 		select {
 		case result := <-respChan:
 			callback(result)
-		case <-time.After(5 * time.Second):
+		case <-time.After(timeout):
 			callback(EngineResult{
 				GameID: gameID,
 				Error:  fmt.Errorf("engine timeout"),
@@ -206,4 +418,3 @@ func (q *EngineQueue) Shutdown(timeout time.Duration) error {
 		return fmt.Errorf("shutdown timeout exceeded")
 	}
 }
-
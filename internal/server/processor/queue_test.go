@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"chess/internal/server/core"
+	"chess/internal/server/engine"
+	"chess/internal/server/engine/enginetest"
+)
+
+const startFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+func TestQueueRelaysProgressAndResult(t *testing.T) {
+	q := NewEngineQueue(1, enginetest.Factory("e2e4"))
+	defer q.Shutdown(time.Second)
+
+	progress := make(chan string, 1)
+	q.onInfo = func(gameID string, update engine.SearchResult) {
+		select {
+		case progress <- gameID:
+		default:
+		}
+	}
+
+	results := make(chan EngineResult, 1)
+	player := &core.Player{Type: core.PlayerComputer, SearchTime: 100}
+	if err := q.SubmitAsync("g1", startFEN, core.ColorWhite, player, func(r EngineResult) { results <- r }); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	select {
+	case r := <-results:
+		if r.Error != nil || r.Move != "e2e4" {
+			t.Fatalf("result = %q (%v), want e2e4", r.Move, r.Error)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result from the queue")
+	}
+
+	select {
+	case gameID := <-progress:
+		if gameID != "g1" {
+			t.Fatalf("progress for %s, want g1", gameID)
+		}
+	default:
+		t.Fatal("no progress update before the result")
+	}
+}
+
+func TestQueueReportsNoMoveInMate(t *testing.T) {
+	q := NewEngineQueue(1, enginetest.Factory())
+	defer q.Shutdown(time.Second)
+
+	// Fool's mate, white to move and mated
+	fen := "rnb1kbnr/pppp1ppp/8/4p3/6Pq/5P2/PPPPP2P/RNBQKBNR w KQkq - 1 3"
+	results := make(chan EngineResult, 1)
+	player := &core.Player{Type: core.PlayerComputer, SearchTime: 100}
+	if err := q.SubmitAsync("g1", fen, core.ColorWhite, player, func(r EngineResult) { results <- r }); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	select {
+	case r := <-results:
+		if r.Move != "" || !r.IsMate {
+			t.Fatalf("result = %q mate=%v, want no move and mate", r.Move, r.IsMate)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no result from the queue")
+	}
+}
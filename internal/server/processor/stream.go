@@ -0,0 +1,158 @@
+package processor
+
+import (
+	"fmt"
+	"sync"
+
+	"chess/internal/server/core"
+	"chess/internal/server/engine"
+)
+
+// searchInfoBuffer is the number of progress events a slow subscriber may
+// fall behind before further events are dropped for it
+const searchInfoBuffer = 32
+
+// searchStreams relays progress of running computer move searches to subscribers
+type searchStreams struct {
+	active map[string]*searchStream // Game ID to its running search
+	mu     sync.Mutex
+}
+
+// searchStream is one computer move search and the clients following it
+type searchStream struct {
+	subscribers map[chan core.SearchInfo]struct{}
+	last        *core.SearchInfo // Latest progress, replayed to late subscribers
+	done        chan struct{}    // Closed once the search returned
+	move        string
+	err         error
+}
+
+// SearchSubscription follows one computer move search
+// Info delivers progress until Done is closed, Result then returns the outcome
+type SearchSubscription struct {
+	Info   <-chan core.SearchInfo
+	Done   <-chan struct{}
+	ch     chan core.SearchInfo
+	stream *searchStream
+	hub    *searchStreams
+}
+
+func newSearchStreams() *searchStreams {
+	return &searchStreams{active: make(map[string]*searchStream)}
+}
+
+// begin registers a search for gameID, a nil hub ignores it
+func (h *searchStreams) begin(gameID string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.active[gameID] = &searchStream{
+		subscribers: make(map[chan core.SearchInfo]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// publish passes engine progress to the game's subscribers without blocking the search
+func (h *searchStreams) publish(gameID string, progress engine.SearchResult) {
+	if h == nil {
+		return
+	}
+	info := core.SearchInfo{Depth: progress.Depth, Score: progress.Score, PV: progress.PV}
+	if progress.IsMate {
+		info.Mate = progress.MateIn
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stream, ok := h.active[gameID]
+	if !ok {
+		return
+	}
+	stream.last = &info
+	for ch := range stream.subscribers {
+		select {
+		case ch <- info:
+		default:
+		}
+	}
+}
+
+// end records the search outcome and releases the game's subscribers
+func (h *searchStreams) end(gameID, move string, err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stream, ok := h.active[gameID]
+	if !ok {
+		return
+	}
+	delete(h.active, gameID)
+	stream.move, stream.err = move, err
+	close(stream.done)
+}
+
+// subscribe follows the running search of gameID, false when none is running
+func (h *searchStreams) subscribe(gameID string) (*SearchSubscription, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stream, ok := h.active[gameID]
+	if !ok {
+		return nil, false
+	}
+
+	ch := make(chan core.SearchInfo, searchInfoBuffer)
+	if stream.last != nil {
+		ch <- *stream.last
+	}
+	stream.subscribers[ch] = struct{}{}
+	return &SearchSubscription{Info: ch, Done: stream.done, ch: ch, stream: stream, hub: h}, true
+}
+
+// Result returns the move the engine chose, valid once Done is closed
+func (s *SearchSubscription) Result() (string, error) {
+	return s.stream.move, s.stream.err
+}
+
+// Close stops delivery to the subscription
+func (s *SearchSubscription) Close() {
+	s.hub.mu.Lock()
+	delete(s.stream.subscribers, s.ch)
+	s.hub.mu.Unlock()
+}
+
+// SetSearchStream lets clients follow the depth and score of running computer
+// move searches through SubscribeSearch
+func (p *Processor) SetSearchStream(enabled bool) {
+	if !enabled || p.queue == nil {
+		p.searchStreams = nil
+		if p.queue != nil {
+			p.queue.onInfo = nil
+		}
+		return
+	}
+	p.searchStreams = newSearchStreams()
+	p.queue.onInfo = p.searchStreams.publish
+}
+
+// SubscribeSearch follows the computer move search running for gameID
+func (p *Processor) SubscribeSearch(gameID string) (*SearchSubscription, *core.ErrorResponse) {
+	if p.searchStreams == nil {
+		return nil, p.errorResponse("search streaming disabled", core.ErrInvalidRequest).Error
+	}
+	g, err := p.svc.GetGame(gameID)
+	if err != nil {
+		return nil, p.errorResponse("game not found", core.ErrGameNotFound).Error
+	}
+
+	sub, ok := p.searchStreams.subscribe(gameID)
+	if !ok {
+		resp := p.errorResponse("no computer move in progress", core.ErrInvalidRequest)
+		resp.Error.Details = fmt.Sprintf("game state is %s", g.State())
+		return nil, resp.Error
+	}
+	return sub, nil
+}
@@ -1,9 +1,12 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"time"
 
+	"chess/internal/server/board"
 	"chess/internal/server/core"
 	"chess/internal/server/game"
 	"chess/internal/server/storage"
@@ -11,13 +14,29 @@ import (
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrGameExists is returned when a game ID is already in use locally or in shared storage
+	ErrGameExists = errors.New("game already exists")
+	// ErrMoveCountMismatch is returned when a conditional move finds the game has changed
+	ErrMoveCountMismatch = errors.New("move count mismatch")
+	// ErrGameCapacity is returned when the in-memory game limit is reached
+	ErrGameCapacity = errors.New("game capacity reached")
+)
+
 // CreateGame registers a new game with pre-constructed players
 func (s *Service) CreateGame(id string, whitePlayer, blackPlayer *core.Player, initialFEN string, startingTurn core.Color) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if _, exists := s.games[id]; exists {
-		return fmt.Errorf("game %s already exists", id)
+		return fmt.Errorf("%w: %s", ErrGameExists, id)
+	}
+
+	// Check the in-memory game limit, making room by evicting a finished game if allowed
+	if s.maxGames > 0 && len(s.games) >= s.maxGames {
+		if !s.evictFinished || !s.evictOldestFinished() {
+			return fmt.Errorf("%w (%d/%d)", ErrGameCapacity, len(s.games), s.maxGames)
+		}
 	}
 
 	// Check computer game limit
@@ -29,10 +48,8 @@ func (s *Service) CreateGame(id string, whitePlayer, blackPlayer *core.Player, i
 		s.computerGames.Add(1)
 	}
 
-	// Store game with provided players
-	s.games[id] = game.New(initialFEN, whitePlayer, blackPlayer, startingTurn)
-
-	// Persist if storage enabled
+	// Persist synchronously if storage enabled, the primary key rejects IDs
+	// already taken by another instance sharing the database
 	if s.store != nil {
 		record := storage.GameRecord{
 			GameID:          id,
@@ -47,9 +64,35 @@ func (s *Service) CreateGame(id string, whitePlayer, blackPlayer *core.Player, i
 			BlackSearchTime: blackPlayer.SearchTime,
 			StartTimeUTC:    time.Now().UTC(),
 		}
-		s.store.RecordNewGame(record)
+		if err := s.store.InsertGame(record); err != nil {
+			if errors.Is(err, storage.ErrDuplicateGame) {
+				if hasComputer {
+					s.computerGames.Add(-1)
+				}
+				return fmt.Errorf("%w: %s", ErrGameExists, id)
+			}
+			// Other storage failures do not block gameplay
+			log.Printf("Failed to persist game %s: %v", id, err)
+		}
+	}
+
+	// Store game with provided players
+	s.games[id] = game.New(initialFEN, whitePlayer, blackPlayer, startingTurn)
+
+	return nil
+}
+
+// SetPreviousGame links a rematch to the game it was created from
+func (s *Service) SetPreviousGame(gameID, previousGameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return fmt.Errorf("game not found: %s", gameID)
 	}
 
+	g.SetPreviousGameID(previousGameID)
 	return nil
 }
 
@@ -70,15 +113,124 @@ func (s *Service) UpdatePlayers(gameID string, whitePlayer, blackPlayer *core.Pl
 }
 
 // GetGame retrieves a game by ID
+// On a memory miss with storage enabled the game is loaded from the shared
+// database, so games created by other instances can be served. A reload
+// replaces the cached game, callers holding the previous *game.Game keep a
+// stale copy until their next GetGame, writes through the service look the
+// game up by ID and apply to the reloaded copy
 func (s *Service) GetGame(gameID string) (*game.Game, error) {
 	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	g, ok := s.games[gameID]
-	if !ok {
+	checked, shared := s.sharedGames[gameID]
+	_, deleted := s.deletedGames[gameID]
+	moveCount := 0
+	if ok {
+		moveCount = len(g.Moves())
+	}
+	s.mu.RUnlock()
+
+	// Games created here are only written here, loaded games are checked
+	// at most once per staleCheckInterval
+	if ok && (!shared || time.Since(checked) < staleCheckInterval) {
+		return g, nil
+	}
+	if s.store == nil || deleted {
+		if ok {
+			return g, nil
+		}
+		return nil, fmt.Errorf("game not found: %s", gameID)
+	}
+
+	// A loaded game is stale if another instance recorded more moves. This
+	// instance's own writes never exceed its copy, and an undo here defers
+	// the check until its queued delete has had time to commit
+	if ok {
+		s.mu.Lock()
+		if _, ok := s.sharedGames[gameID]; ok {
+			s.sharedGames[gameID] = time.Now()
+		}
+		s.mu.Unlock()
+		count, err := s.store.CountMoves(gameID)
+		if err != nil || count <= moveCount {
+			return g, nil
+		}
+	}
+
+	return s.loadGame(gameID)
+}
+
+// loadGame reconstructs a game from storage and caches it
+func (s *Service) loadGame(gameID string) (*game.Game, error) {
+	records, err := s.store.QueryGames(gameID, "")
+	if err != nil || len(records) == 0 {
 		return nil, fmt.Errorf("game not found: %s", gameID)
 	}
-	return g, nil
+	record := records[0]
+
+	moves, err := s.store.GetMoves(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load moves: %w", err)
+	}
+
+	b, err := board.ParseFEN(record.InitialFEN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored FEN: %w", err)
+	}
+
+	whitePlayer := s.playerFromRecord(record.WhitePlayerID, core.ColorWhite, record.WhiteType, record.WhiteLevel, record.WhiteSearchTime)
+	blackPlayer := s.playerFromRecord(record.BlackPlayerID, core.ColorBlack, record.BlackType, record.BlackLevel, record.BlackSearchTime)
+
+	loadedGame := game.New(record.InitialFEN, whitePlayer, blackPlayer, b.Turn())
+	turn := b.Turn()
+	for _, m := range moves {
+		turn = core.OppositeColor(turn)
+		loadedGame.AddSnapshot(m.FENAfterMove, m.MoveUCI, turn)
+	}
+	loadedGame.SetState(core.StateFromResult(record.Result))
+	switch record.Reason {
+	case core.ReasonAborted:
+		loadedGame.SetState(core.StateAborted)
+	case core.ReasonStalemate:
+		loadedGame.SetState(core.StateStalemate)
+	}
+	loadedGame.SetReason(record.Reason)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Another request may have loaded or advanced the game meanwhile
+	existing, ok := s.games[gameID]
+	if ok && len(existing.Moves()) >= len(moves) {
+		return existing, nil
+	}
+	if !ok && loadedGame.HasComputerPlayer() {
+		s.computerGames.Add(1)
+	}
+
+	s.games[gameID] = loadedGame
+	s.sharedGames[gameID] = time.Now()
+	if ok {
+		s.waiter.NotifyGame(gameID, len(moves))
+	}
+
+	return loadedGame, nil
+}
+
+// playerFromRecord rebuilds a player from stored columns
+// Human slots whose ID belongs to a registered user are treated as claimed
+func (s *Service) playerFromRecord(id string, color core.Color, playerType, level, searchTime int) *core.Player {
+	player := &core.Player{
+		ID:    id,
+		Color: color,
+		Type:  core.PlayerType(playerType),
+	}
+	if player.Type == core.PlayerComputer {
+		player.Level = level
+		player.SearchTime = searchTime
+	} else if _, err := s.store.GetUserByID(id); err == nil {
+		player.ClaimedBy = id
+	}
+	return player
 }
 
 // GenerateGameID creates a new unique game ID
@@ -89,14 +241,23 @@ func (s *Service) GenerateGameID() string {
 	// Ensure UUID uniqueness (handle potential conflicts)
 	for {
 		id := uuid.New().String()
-		if _, exists := s.games[id]; !exists {
-			return id
+		if _, exists := s.games[id]; exists {
+			continue
+		}
+		// Other instances sharing the database may have used the ID
+		if s.store != nil {
+			if exists, err := s.store.GameExists(id); err == nil && exists {
+				continue
+			}
 		}
+		return id
 	}
 }
 
 // ApplyMove adds a validated move to the game history
-func (s *Service) ApplyMove(gameID, moveUCI, newFEN string) error {
+// A non-negative expectedMoveCount makes the apply conditional on the game
+// still having that many moves, checked atomically under the service mutex
+func (s *Service) ApplyMove(gameID, moveUCI, newFEN string, expectedMoveCount int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -105,9 +266,14 @@ func (s *Service) ApplyMove(gameID, moveUCI, newFEN string) error {
 		return fmt.Errorf("game not found: %s", gameID)
 	}
 
+	if expectedMoveCount >= 0 && len(g.Moves()) != expectedMoveCount {
+		return fmt.Errorf("%w: expected %d moves, game has %d", ErrMoveCountMismatch, expectedMoveCount, len(g.Moves()))
+	}
+
 	// Determine whose turn it was before this move
 	currentTurn := g.NextTurnColor()
 	nextTurn := core.OppositeColor(currentTurn)
+	previousFEN := g.CurrentFEN()
 
 	// Add the new position to game history
 	g.AddSnapshot(newFEN, moveUCI, nextTurn)
@@ -122,18 +288,58 @@ func (s *Service) ApplyMove(gameID, moveUCI, newFEN string) error {
 			GameID:       gameID,
 			MoveNumber:   moveNumber,
 			MoveUCI:      moveUCI,
+			MoveSAN:      moveSAN(previousFEN, moveUCI),
 			FENAfterMove: newFEN,
 			PlayerColor:  currentTurn.String(),
 			MoveTimeUTC:  time.Now().UTC(),
 		}
 		s.store.RecordMove(record)
+		s.markWritten(gameID)
 	}
 
 	return nil
 }
 
+// markWritten defers the staleness check of a loaded game this instance
+// just wrote, caller must hold mu
+func (s *Service) markWritten(gameID string) {
+	if _, ok := s.sharedGames[gameID]; ok {
+		s.sharedGames[gameID] = time.Now()
+	}
+}
+
+// removeStoredMoves drops the moves after afterMoveNumber from storage,
+// archiving them in the undo log when undo history is kept
+func (s *Service) removeStoredMoves(gameID string, afterMoveNumber int) {
+	if s.keepUndoHistory {
+		s.store.ArchiveUndoneMoves(gameID, afterMoveNumber)
+		return
+	}
+	s.store.DeleteUndoneMoves(gameID, afterMoveNumber)
+}
+
+// moveSAN renders a move in SAN for storage while the position before it is
+// at hand, empty if the position cannot be parsed or the move is not legal in it
+func moveSAN(fen, uci string) string {
+	b, err := board.ParseFEN(fen)
+	if err != nil {
+		return ""
+	}
+	san, err := b.ToSAN(uci)
+	if err != nil {
+		return ""
+	}
+	return san
+}
+
 // UpdateGameState sets the game's end state (checkmate, stalemate, etc)
 func (s *Service) UpdateGameState(gameID string, state core.State) error {
+	return s.EndGame(gameID, state, "")
+}
+
+// EndGame sets a state together with the reason the game ended, the reason is
+// kept only for terminal states
+func (s *Service) EndGame(gameID string, state core.State, reason string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -142,13 +348,22 @@ func (s *Service) UpdateGameState(gameID string, state core.State) error {
 		return fmt.Errorf("game not found: %s", gameID)
 	}
 
+	wasOver := g.State().IsGameOver()
 	g.SetState(state)
+	if state.IsGameOver() {
+		g.SetReason(reason)
+	}
 
 	// Notify if game ended
 	if state != core.StateOngoing && state != core.StatePending {
 		s.waiter.NotifyGame(gameID, len(g.Moves()))
 	}
 
+	// Persist result when the game ends or is reopened by undo
+	if s.store != nil && (state.IsGameOver() || wasOver) {
+		s.store.UpdateGameResult(gameID, state.Result(), g.Reason())
+	}
+
 	return nil
 }
 
@@ -188,7 +403,35 @@ func (s *Service) UndoMoves(gameID string, count int) error {
 	// Delete undone moves from storage if enabled
 	if s.store != nil {
 		remainingMoves := originalMoveCount - count
-		s.store.DeleteUndoneMoves(gameID, remainingMoves)
+		s.removeStoredMoves(gameID, remainingMoves)
+		s.markWritten(gameID)
+	}
+
+	return nil
+}
+
+// ResetGame clears all moves and returns the game to its starting position
+func (s *Service) ResetGame(gameID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[gameID]
+	if !ok {
+		return fmt.Errorf("game not found: %s", gameID)
+	}
+
+	wasOver := g.State().IsGameOver()
+	g.Reset()
+
+	// Notify waiting clients about the reset
+	s.waiter.NotifyGame(gameID, 0)
+
+	if s.store != nil {
+		s.removeStoredMoves(gameID, 0)
+		s.markWritten(gameID)
+		if wasOver {
+			s.store.UpdateGameResult(gameID, g.State().Result(), "")
+		}
 	}
 
 	return nil
@@ -204,6 +447,13 @@ func (s *Service) DeleteGame(gameID string) error {
 		return fmt.Errorf("game not found: %s", gameID)
 	}
 
+	s.removeGame(gameID, g)
+	s.deletedGames[gameID] = time.Now()
+	return nil
+}
+
+// removeGame drops a game from memory, caller must hold mu
+func (s *Service) removeGame(gameID string, g *game.Game) {
 	// Decrement computer game count if applicable
 	if g.HasComputerPlayer() {
 		s.computerGames.Add(-1)
@@ -213,5 +463,37 @@ func (s *Service) DeleteGame(gameID string) error {
 	s.waiter.RemoveGame(gameID)
 
 	delete(s.games, gameID)
-	return nil
+	delete(s.sharedGames, gameID)
+}
+
+// evictOldestFinished drops the finished game unchanged for longest, false
+// when every game is still in play. Stored games reload on their next access
+// Caller must hold mu
+func (s *Service) evictOldestFinished() bool {
+	var (
+		oldestID string
+		oldest   *game.Game
+	)
+	for id, g := range s.games {
+		if !g.State().IsGameOver() {
+			continue
+		}
+		if oldest == nil || g.UpdatedAt().Before(oldest.UpdatedAt()) {
+			oldestID, oldest = id, g
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+
+	s.removeGame(oldestID, oldest)
+	log.Printf("Game capacity reached, evicted finished game %s", oldestID)
+	return true
+}
+
+// GameCount returns the number of games held in memory
+func (s *Service) GameCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.games)
 }
\ No newline at end of file
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lixenwraith/auth"
+)
+
+// Argon2id parameter bounds, below the minimums hashes are too cheap to brute
+// force, above the maximums a single login can exhaust a small server
+const (
+	MinArgonMemory     = 8 * 1024    // 8 MiB in KiB
+	MaxArgonMemory     = 1024 * 1024 // 1 GiB in KiB
+	MinArgonIterations = 1
+	MaxArgonIterations = 10
+	MinArgonThreads    = 1
+	MaxArgonThreads    = 16
+)
+
+// PasswordParams configures Argon2id password hashing
+// Memory is in KiB, matching the m= field of the stored PHC hash
+type PasswordParams struct {
+	Memory     uint32
+	Iterations uint32
+	Threads    uint8
+}
+
+// DefaultPasswordParams returns the auth library defaults
+func DefaultPasswordParams() PasswordParams {
+	return PasswordParams{
+		Memory:     auth.DefaultArgonMemory,
+		Iterations: auth.DefaultArgonTime,
+		Threads:    auth.DefaultArgonThreads,
+	}
+}
+
+// NewPasswordParams validates flag values and builds the hashing parameters
+func NewPasswordParams(memory, iterations, threads uint) (PasswordParams, error) {
+	if memory < MinArgonMemory || memory > MaxArgonMemory {
+		return PasswordParams{}, fmt.Errorf("argon2 memory must be between %d and %d KiB", MinArgonMemory, MaxArgonMemory)
+	}
+	if iterations < MinArgonIterations || iterations > MaxArgonIterations {
+		return PasswordParams{}, fmt.Errorf("argon2 iterations must be between %d and %d", MinArgonIterations, MaxArgonIterations)
+	}
+	if threads < MinArgonThreads || threads > MaxArgonThreads {
+		return PasswordParams{}, fmt.Errorf("argon2 parallelism must be between %d and %d", MinArgonThreads, MaxArgonThreads)
+	}
+	return PasswordParams{
+		Memory:     uint32(memory),
+		Iterations: uint32(iterations),
+		Threads:    uint8(threads),
+	}, nil
+}
+
+// HashPassword creates an Argon2id PHC hash of password using these parameters
+func (p PasswordParams) HashPassword(password string) (string, error) {
+	return auth.HashPassword(password,
+		auth.WithMemory(p.Memory),
+		auth.WithTime(p.Iterations),
+		auth.WithThreads(p.Threads),
+	)
+}
+
+// NeedsRehash reports whether phcHash was created with different parameters
+// Unparseable hashes are left alone, VerifyPassword already accepted them
+func (p PasswordParams) NeedsRehash(phcHash string) bool {
+	if auth.ValidatePHCHashFormat(phcHash) != nil {
+		return false
+	}
+	var stored PasswordParams
+	if _, err := fmt.Sscanf(strings.Split(phcHash, "$")[3], "m=%d,t=%d,p=%d",
+		&stored.Memory, &stored.Iterations, &stored.Threads); err != nil {
+		return false
+	}
+	return stored != p
+}
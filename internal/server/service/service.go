@@ -20,8 +20,26 @@ const (
 	TempUserTTL        = 24 * time.Hour
 	SessionTTL         = 7 * 24 * time.Hour
 	CleanupJobInterval = 1 * time.Hour
+	DeletedGameTTL     = 24 * time.Hour
+
+	// staleCheckInterval is how often a game loaded from shared storage is
+	// checked for moves made by other instances
+	staleCheckInterval = 1 * time.Second
+
+	// MinJWTSecretLength is the shortest HS256 signing secret accepted
+	MinJWTSecretLength = 32
 )
 
+// ErrAuthDisabled is returned by token operations of a service without a JWT secret
+var ErrAuthDisabled = errors.New("authentication disabled")
+
+// Options configures a Service, every field is optional
+type Options struct {
+	Store          *storage.Store  // Persistence, games and users stay in memory without it
+	JWTSecret      []byte          // Token signing secret, empty disables token issue and validation
+	PasswordParams *PasswordParams // Argon2id parameters for new hashes, nil uses the defaults
+}
+
 // Service coordinates game state, user management, and storage
 type Service struct {
 	games         map[string]*game.Game
@@ -30,16 +48,72 @@ type Service struct {
 	jwtSecret     []byte
 	waiter        *WaitRegistry
 	computerGames atomic.Int32 // Active games with computer players
+
+	// Games loaded from shared storage to when they were last checked for
+	// moves made by other instances or last written here
+	sharedGames map[string]time.Time
+	// Recently deleted games, not reloaded from storage until pruned
+	deletedGames map[string]time.Time
+
+	// Finished game retention, disabled when zero
+	gameRetention   time.Duration
+	retentionDryRun bool
+
+	// Undone moves go to the undo log instead of being deleted
+	keepUndoHistory bool
+
+	// In-memory game limit, disabled when zero
+	maxGames      int
+	evictFinished bool
+
+	// Argon2id parameters for new password hashes
+	passwordParams PasswordParams
 }
 
-// New creates a new service instance with optional storage
-func New(store *storage.Store, jwtSecret []byte) *Service {
-	return &Service{
-		games:     make(map[string]*game.Game),
-		store:     store,
-		jwtSecret: jwtSecret,
-		waiter:    NewWaitRegistry(),
+// New creates a new service instance from opts
+func New(opts Options) (*Service, error) {
+	if len(opts.JWTSecret) > 0 && len(opts.JWTSecret) < MinJWTSecretLength {
+		return nil, fmt.Errorf("JWT secret must be at least %d bytes", MinJWTSecretLength)
+	}
+	passwordParams := DefaultPasswordParams()
+	if opts.PasswordParams != nil {
+		passwordParams = *opts.PasswordParams
 	}
+
+	return &Service{
+		games:          make(map[string]*game.Game),
+		store:          opts.Store,
+		jwtSecret:      opts.JWTSecret,
+		waiter:         NewWaitRegistry(),
+		sharedGames:    make(map[string]time.Time),
+		deletedGames:   make(map[string]time.Time),
+		passwordParams: passwordParams,
+	}, nil
+}
+
+// SetGameRetention enables cleanup of finished games older than retention
+// In dry-run mode the cleanup job only logs how many games would be deleted
+func (s *Service) SetGameRetention(retention time.Duration, dryRun bool) {
+	s.gameRetention = retention
+	s.retentionDryRun = dryRun
+}
+
+// SetGameCapacity limits the games held in memory, new games are refused once
+// maxGames is reached unless evictFinished lets the oldest finished game go
+func (s *Service) SetGameCapacity(maxGames int, evictFinished bool) {
+	s.maxGames = maxGames
+	s.evictFinished = evictFinished
+}
+
+// MaxGames returns the in-memory game limit, zero when unlimited
+func (s *Service) MaxGames() int {
+	return s.maxGames
+}
+
+// SetKeepUndoHistory records moves taken back by undo or reset in the undo
+// log, reloaded games still replay only the moves that stand
+func (s *Service) SetKeepUndoHistory(enabled bool) {
+	s.keepUndoHistory = enabled
 }
 
 // GetStorageHealth returns the storage component status
@@ -116,6 +190,7 @@ func (s *Service) Shutdown(timeout time.Duration) error {
 	defer s.mu.Unlock()
 
 	s.games = make(map[string]*game.Game)
+	s.sharedGames = make(map[string]time.Time)
 
 	if s.store != nil {
 		if err := s.store.Close(); err != nil {
@@ -146,6 +221,15 @@ func (s *Service) cleanupExpired() {
 		return
 	}
 
+	// Forget old deletions so the tombstone set stays bounded
+	s.mu.Lock()
+	for id, deletedAt := range s.deletedGames {
+		if time.Since(deletedAt) > DeletedGameTTL {
+			delete(s.deletedGames, id)
+		}
+	}
+	s.mu.Unlock()
+
 	// Cleanup expired temp users
 	if deleted, err := s.store.DeleteExpiredTempUsers(); err != nil {
 		// Log but don't fail
@@ -160,4 +244,20 @@ func (s *Service) cleanupExpired() {
 	} else if deleted > 0 {
 		fmt.Printf("cleanup: deleted %d expired sessions\n", deleted)
 	}
+
+	// Cleanup finished games past retention
+	if s.gameRetention > 0 {
+		cutoff := time.Now().UTC().Add(-s.gameRetention)
+		if s.retentionDryRun {
+			if count, err := s.store.CountGamesOlderThan(cutoff); err != nil {
+				fmt.Printf("cleanup: failed to count expired games: %v\n", err)
+			} else if count > 0 {
+				fmt.Printf("cleanup: dry-run, would delete %d finished games ended before %s\n", count, cutoff.Format(time.RFC3339))
+			}
+		} else if deleted, err := s.store.DeleteGamesOlderThan(cutoff); err != nil {
+			fmt.Printf("cleanup: failed to delete expired games: %v\n", err)
+		} else if deleted > 0 {
+			fmt.Printf("cleanup: deleted %d finished games ended before %s\n", deleted, cutoff.Format(time.RFC3339))
+		}
+	}
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ var (
 	ErrStorageDisabled    = errors.New("storage disabled")
 	ErrAtCapacity         = errors.New("at capacity")
 	ErrPermanentSlotsFull = errors.New("permanent slots full")
+	ErrSessionNotFound    = errors.New("session not found")
 )
 
 // User represents a registered user account
@@ -28,6 +30,21 @@ type User struct {
 	ExpiresAt   *time.Time
 }
 
+// SessionMeta describes the client a session was created for
+type SessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
+
+// Session is an active login of a user
+type Session struct {
+	SessionID string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UserAgent string
+	IPAddress string
+}
+
 // CreateUser creates new user with registration limits enforcement
 func (s *Service) CreateUser(username, email, password string, permanent bool) (*User, error) {
 	if s.store == nil {
@@ -62,7 +79,7 @@ func (s *Service) CreateUser(username, email, password string, permanent bool) (
 	}
 
 	// Hash password
-	passwordHash, err := auth.HashPassword(password)
+	passwordHash, err := s.passwordParams.HashPassword(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -119,7 +136,7 @@ func (s *Service) removeOldestTempUser() error {
 }
 
 // AuthenticateUser verifies credentials and creates a new session
-func (s *Service) AuthenticateUser(identifier, password string) (*User, string, error) {
+func (s *Service) AuthenticateUser(identifier, password string, meta SessionMeta) (*User, string, error) {
 	if s.store == nil {
 		return nil, "", fmt.Errorf("storage disabled")
 	}
@@ -135,7 +152,7 @@ func (s *Service) AuthenticateUser(identifier, password string) (*User, string,
 	}
 
 	if err != nil {
-		auth.HashPassword(password) // Timing attack prevention
+		s.passwordParams.HashPassword(password) // Timing attack prevention
 		return nil, "", fmt.Errorf("invalid credentials")
 	}
 
@@ -151,13 +168,20 @@ func (s *Service) AuthenticateUser(identifier, password string) (*User, string,
 		}
 	}
 
-	// Create new session (invalidates any existing session)
+	// Upgrade hashes made with outdated parameters while the plaintext is known
+	if s.passwordParams.NeedsRehash(userRecord.PasswordHash) {
+		s.rehashPassword(userRecord.UserID, password)
+	}
+
+	// Create new session, the user's other sessions stay valid
 	sessionID := uuid.New().String()
 	sessionRecord := storage.SessionRecord{
 		SessionID: sessionID,
 		UserID:    userRecord.UserID,
 		CreatedAt: time.Now().UTC(),
 		ExpiresAt: time.Now().UTC().Add(SessionTTL),
+		UserAgent: meta.UserAgent,
+		IPAddress: meta.IPAddress,
 	}
 
 	if err := s.store.CreateSession(sessionRecord); err != nil {
@@ -177,6 +201,18 @@ func (s *Service) AuthenticateUser(identifier, password string) (*User, string,
 	}, sessionID, nil
 }
 
+// rehashPassword stores a new hash of password with the current parameters
+// Failures are logged only, the old hash keeps working
+func (s *Service) rehashPassword(userID, password string) {
+	passwordHash, err := s.passwordParams.HashPassword(password)
+	if err == nil {
+		err = s.store.UpdateUserPassword(userID, passwordHash)
+	}
+	if err != nil {
+		log.Printf("Failed to rehash password for user %s: %v", userID, err)
+	}
+}
+
 // ValidateSession checks if a session is valid
 func (s *Service) ValidateSession(sessionID string) (bool, error) {
 	if s.store == nil {
@@ -193,6 +229,46 @@ func (s *Service) InvalidateSession(sessionID string) error {
 	return s.store.DeleteSession(sessionID)
 }
 
+// ListUserSessions returns the user's unexpired sessions, newest first
+func (s *Service) ListUserSessions(userID string) ([]Session, error) {
+	if s.store == nil {
+		return nil, ErrStorageDisabled
+	}
+
+	records, err := s.store.ListSessionsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]Session, 0, len(records))
+	for _, r := range records {
+		sessions = append(sessions, Session{
+			SessionID: r.SessionID,
+			CreatedAt: r.CreatedAt,
+			ExpiresAt: r.ExpiresAt,
+			UserAgent: r.UserAgent,
+			IPAddress: r.IPAddress,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeUserSession ends one of the user's sessions, tokens issued for it stop validating
+func (s *Service) RevokeUserSession(userID, sessionID string) error {
+	if s.store == nil {
+		return ErrStorageDisabled
+	}
+
+	deleted, err := s.store.DeleteUserSession(userID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !deleted {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
 // GetUserByID retrieves user information by user ID
 func (s *Service) GetUserByID(userID string) (*User, error) {
 	if s.store == nil {
@@ -227,11 +303,17 @@ func (s *Service) GenerateUserToken(userID, sessionID string) (string, error) {
 		"session_id": sessionID,
 	}
 
+	if len(s.jwtSecret) == 0 {
+		return "", ErrAuthDisabled
+	}
 	return auth.GenerateHS256Token(s.jwtSecret, userID, claims, SessionTTL)
 }
 
 // ValidateToken verifies JWT token and session validity
 func (s *Service) ValidateToken(token string) (string, map[string]any, error) {
+	if len(s.jwtSecret) == 0 {
+		return "", nil, ErrAuthDisabled
+	}
 	userID, claims, err := auth.ValidateHS256Token(s.jwtSecret, token)
 	if err != nil {
 		return "", nil, err
@@ -264,7 +346,7 @@ func (s *Service) generateUniqueUserID() (string, error) {
 
 // CreateUserSession creates a session for a user without re-authenticating
 // Used after registration to avoid redundant password hashing
-func (s *Service) CreateUserSession(userID string) (string, error) {
+func (s *Service) CreateUserSession(userID string, meta SessionMeta) (string, error) {
 	if s.store == nil {
 		return "", fmt.Errorf("storage disabled")
 	}
@@ -275,6 +357,8 @@ func (s *Service) CreateUserSession(userID string) (string, error) {
 		UserID:    userID,
 		CreatedAt: time.Now().UTC(),
 		ExpiresAt: time.Now().UTC().Add(SessionTTL),
+		UserAgent: meta.UserAgent,
+		IPAddress: meta.IPAddress,
 	}
 
 	if err := s.store.CreateSession(sessionRecord); err != nil {
@@ -283,4 +367,3 @@ func (s *Service) CreateUserSession(userID string) (string, error) {
 
 	return sessionID, nil
 }
-
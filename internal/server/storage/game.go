@@ -2,39 +2,55 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
 )
 
-// RecordNewGame asynchronously records a new game
-func (s *Store) RecordNewGame(record GameRecord) error {
+// ErrDuplicateGame is returned when inserting a game whose ID is already recorded
+var ErrDuplicateGame = errors.New("duplicate game ID")
+
+// GameExists checks whether a game ID is already recorded
+func (s *Store) GameExists(gameID string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM games WHERE game_id = ?)`, gameID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check game: %w", err)
+	}
+	return exists, nil
+}
+
+// InsertGame synchronously records a new game, failing with ErrDuplicateGame
+// if another instance sharing the database already used the ID
+func (s *Store) InsertGame(record GameRecord) error {
 	if !s.healthStatus.Load() {
 		return nil // Silently drop if degraded
 	}
 
-	select {
-	case s.writeChan <- func(tx *sql.Tx) error {
-		query := `INSERT INTO games (
-			game_id, initial_fen, 
-			white_player_id, white_type, white_level, white_search_time,
-			black_player_id, black_type, black_level, black_search_time,
-			start_time_utc
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO games (
+		game_id, initial_fen, 
+		white_player_id, white_type, white_level, white_search_time,
+		black_player_id, black_type, black_level, black_search_time,
+		start_time_utc
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-		_, err := tx.Exec(query,
-			record.GameID, record.InitialFEN,
-			record.WhitePlayerID, record.WhiteType, record.WhiteLevel, record.WhiteSearchTime,
-			record.BlackPlayerID, record.BlackType, record.BlackLevel, record.BlackSearchTime,
-			record.StartTimeUTC,
-		)
-		return err
-	}:
-		return nil
-	default:
-		// Channel full, drop write
-		log.Printf("Storage write queue full, dropping game record")
-		return nil
+	_, err := s.db.Exec(query,
+		record.GameID, record.InitialFEN,
+		record.WhitePlayerID, record.WhiteType, record.WhiteLevel, record.WhiteSearchTime,
+		record.BlackPlayerID, record.BlackType, record.BlackLevel, record.BlackSearchTime,
+		record.StartTimeUTC,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			return ErrDuplicateGame
+		}
+		return fmt.Errorf("failed to insert game: %w", err)
 	}
+	return nil
 }
 
 // RecordMove asynchronously records a move
@@ -46,11 +62,11 @@ func (s *Store) RecordMove(record MoveRecord) error {
 	select {
 	case s.writeChan <- func(tx *sql.Tx) error {
 		query := `INSERT INTO moves (
-			game_id, move_number, move_uci, fen_after_move, player_color, move_time_utc
-		) VALUES (?, ?, ?, ?, ?, ?)`
+			game_id, move_number, move_uci, move_san, fen_after_move, player_color, move_time_utc
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`
 
 		_, err := tx.Exec(query,
-			record.GameID, record.MoveNumber, record.MoveUCI,
+			record.GameID, record.MoveNumber, record.MoveUCI, record.MoveSAN,
 			record.FENAfterMove, record.PlayerColor, record.MoveTimeUTC,
 		)
 		return err
@@ -83,55 +99,351 @@ func (s *Store) DeleteUndoneMoves(gameID string, afterMoveNumber int) error {
 	}
 }
 
-// QueryGames retrieves games with optional filtering
-func (s *Store) QueryGames(gameID, playerID string) ([]GameRecord, error) {
-	query := `SELECT 
-		game_id, initial_fen, 
-		white_player_id, white_type, white_level, white_search_time,
-		black_player_id, black_type, black_level, black_search_time,
-		start_time_utc
-	FROM games WHERE 1=1`
+// UpdateGameResult asynchronously records a game's result and end reason
+// "*" with an empty reason clears both (e.g. after undo), "*" with a reason ends the game without a result
+func (s *Store) UpdateGameResult(gameID, result, reason string) error {
+	if !s.healthStatus.Load() {
+		return nil // Silently drop if degraded
+	}
+
+	var endTime *time.Time
+	if result != "*" || reason != "" {
+		now := time.Now().UTC()
+		endTime = &now
+	}
+
+	select {
+	case s.writeChan <- func(tx *sql.Tx) error {
+		query := `UPDATE games SET result = ?, reason = ?, end_time_utc = ? WHERE game_id = ?`
+		_, err := tx.Exec(query, result, reason, endTime, gameID)
+		return err
+	}:
+		return nil
+	default:
+		// Channel full, drop write
+		log.Printf("Storage write queue full, dropping game result")
+		return nil
+	}
+}
+
+// ArchiveUndoneMoves asynchronously moves the moves after afterMoveNumber to
+// the undo log, keeping a record of what was taken back
+func (s *Store) ArchiveUndoneMoves(gameID string, afterMoveNumber int) error {
+	if !s.healthStatus.Load() {
+		return nil // Silently drop if degraded
+	}
+
+	undoneAt := time.Now().UTC()
+	select {
+	case s.writeChan <- func(tx *sql.Tx) error {
+		logQuery := `INSERT INTO undo_log (game_id, move_number, move_uci, undone_at)
+			SELECT game_id, move_number, move_uci, ? FROM moves
+			WHERE game_id = ? AND move_number > ? ORDER BY move_number DESC`
+		if _, err := tx.Exec(logQuery, undoneAt, gameID, afterMoveNumber); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`DELETE FROM moves WHERE game_id = ? AND move_number > ?`, gameID, afterMoveNumber)
+		return err
+	}:
+		return nil
+	default:
+		// Channel full, drop write
+		log.Printf("Storage write queue full, dropping undo operation")
+		return nil
+	}
+}
+
+// GetUndoLog retrieves the moves taken back in a game, in the order they were undone
+func (s *Store) GetUndoLog(gameID string) ([]UndoRecord, error) {
+	query := `SELECT undo_id, game_id, move_number, move_uci, undone_at
+	FROM undo_log WHERE game_id = ? ORDER BY undo_id ASC`
+
+	rows, err := s.db.Query(query, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var undone []UndoRecord
+	for rows.Next() {
+		var u UndoRecord
+		if err := rows.Scan(&u.UndoID, &u.GameID, &u.MoveNumber, &u.MoveUCI, &u.UndoneAt); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		undone = append(undone, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return undone, nil
+}
+
+// CountGamesOlderThan counts finished games that ended before the cutoff
+func (s *Store) CountGamesOlderThan(cutoff time.Time) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM games WHERE end_time_utc < ?`
+	err := s.db.QueryRow(query, cutoff).Scan(&count)
+	return count, err
+}
+
+// DeleteGamesOlderThan removes finished games that ended before the cutoff along with their moves and undo log
+// Unfinished games have no end time and are never removed regardless of age
+func (s *Store) DeleteGamesOlderThan(cutoff time.Time) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Foreign key cascade is per-connection in SQLite, delete moves explicitly
+	movesQuery := `DELETE FROM moves WHERE game_id IN (
+		SELECT game_id FROM games WHERE end_time_utc < ?
+	)`
+	if _, err := tx.Exec(movesQuery, cutoff); err != nil {
+		return 0, fmt.Errorf("failed to delete moves: %w", err)
+	}
+
+	undoQuery := `DELETE FROM undo_log WHERE game_id IN (
+		SELECT game_id FROM games WHERE end_time_utc < ?
+	)`
+	if _, err := tx.Exec(undoQuery, cutoff); err != nil {
+		return 0, fmt.Errorf("failed to delete undo log: %w", err)
+	}
+
+	gamesQuery := `DELETE FROM games WHERE end_time_utc < ?`
+	result, err := tx.Exec(gamesQuery, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete games: %w", err)
+	}
 
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, tx.Commit()
+}
+
+// GetMoves retrieves all moves of a game ordered by move number
+func (s *Store) GetMoves(gameID string) ([]MoveRecord, error) {
+	query := `SELECT
+		move_id, game_id, move_number, move_uci, move_san, fen_after_move, player_color, move_time_utc
+	FROM moves WHERE game_id = ? ORDER BY move_number ASC`
+
+	rows, err := s.db.Query(query, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var moves []MoveRecord
+	for rows.Next() {
+		var m MoveRecord
+		err := rows.Scan(
+			&m.MoveID, &m.GameID, &m.MoveNumber, &m.MoveUCI, &m.MoveSAN,
+			&m.FENAfterMove, &m.PlayerColor, &m.MoveTimeUTC,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		moves = append(moves, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration failed: %w", err)
+	}
+
+	return moves, nil
+}
+
+// CountMoves returns the number of recorded moves for a game
+func (s *Store) CountMoves(gameID string) (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM moves WHERE game_id = ?`, gameID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count moves: %w", err)
+	}
+	return count, nil
+}
+
+// GetOrphanedMoveGameIDs returns game IDs referenced by moves that have no games row
+func (s *Store) GetOrphanedMoveGameIDs() ([]string, error) {
+	query := `SELECT DISTINCT m.game_id FROM moves m
+	LEFT JOIN games g ON g.game_id = m.game_id
+	WHERE g.game_id IS NULL`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GameQuery filters and pages game listings, zero values disable each filter
+type GameQuery struct {
+	GameID   string    // Exact game ID, empty or * for all
+	PlayerID string    // Either side's player ID, empty or * for all
+	Since    time.Time // Games started at or after
+	Until    time.Time // Games started before
+	Limit    int       // Page size, 0 for no limit
+	Offset   int
+	Activity bool // Include move count and last move time, one lookup per listed game
+}
+
+// where builds the filter clause and its arguments
+func (q GameQuery) where() (string, []any) {
+	clause := " WHERE 1=1"
 	var args []any
 
 	// Handle gameID filtering
-	if gameID != "" && gameID != "*" {
-		query += " AND game_id = ?"
-		args = append(args, gameID)
+	if q.GameID != "" && q.GameID != "*" {
+		clause += " AND game_id = ?"
+		args = append(args, q.GameID)
 	}
 
 	// Handle playerID filtering
-	if playerID != "" && playerID != "*" {
-		query += " AND (white_player_id = ? OR black_player_id = ?)"
-		args = append(args, playerID, playerID)
+	if q.PlayerID != "" && q.PlayerID != "*" {
+		clause += " AND (white_player_id = ? OR black_player_id = ?)"
+		args = append(args, q.PlayerID, q.PlayerID)
+	}
+
+	if !q.Since.IsZero() {
+		clause += " AND start_time_utc >= ?"
+		args = append(args, q.Since.UTC())
 	}
+	if !q.Until.IsZero() {
+		clause += " AND start_time_utc < ?"
+		args = append(args, q.Until.UTC())
+	}
+
+	return clause, args
+}
+
+// QueryGames retrieves games with optional filtering
+func (s *Store) QueryGames(gameID, playerID string) ([]GameRecord, error) {
+	where, args := GameQuery{GameID: gameID, PlayerID: playerID}.where()
+	return s.selectGames(where+" ORDER BY start_time_utc DESC", args, false)
+}
+
+// QueryGamesPaged retrieves one page of matching games, newest first, with
+// the total number of matches
+func (s *Store) QueryGamesPaged(q GameQuery) ([]GameRecord, int, error) {
+	where, args := q.where()
 
-	query += " ORDER BY start_time_utc DESC"
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM games"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count failed: %w", err)
+	}
+
+	// SQLite treats a negative limit as no limit
+	limit := q.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	games, err := s.selectGames(where+" ORDER BY start_time_utc DESC LIMIT ? OFFSET ?", append(args, limit, q.Offset), q.Activity)
+	if err != nil {
+		return nil, 0, err
+	}
+	return games, total, nil
+}
+
+// parseTimestamp parses a DATETIME value in any format the SQLite driver writes
+func parseTimestamp(value string) (time.Time, error) {
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", value)
+}
+
+// EachGame calls fn for every stored game, newest first, reading one row at
+// a time so callers need not hold all games in memory. An error from fn
+// stops the iteration and is returned
+func (s *Store) EachGame(fn func(GameRecord) error) error {
+	return s.eachGame(" ORDER BY start_time_utc DESC", nil, false, fn)
+}
+
+// selectGames runs a game query with the given trailing clauses, activity
+// adds each game's move count and last move time from the moves table
+func (s *Store) selectGames(clauses string, args []any, activity bool) ([]GameRecord, error) {
+	var games []GameRecord
+	err := s.eachGame(clauses, args, activity, func(g GameRecord) error {
+		games = append(games, g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+// eachGame runs a game query like selectGames, passing each row to fn as it is read
+func (s *Store) eachGame(clauses string, args []any, activity bool, fn func(GameRecord) error) error {
+	columns := `game_id, initial_fen, 
+		white_player_id, white_type, white_level, white_search_time,
+		black_player_id, black_type, black_level, black_search_time,
+		start_time_utc, result, reason, end_time_utc`
+	if activity {
+		columns += `,
+		(SELECT COUNT(*) FROM moves m WHERE m.game_id = games.game_id),
+		(SELECT MAX(move_time_utc) FROM moves m WHERE m.game_id = games.game_id)`
+	}
+	query := "SELECT " + columns + " FROM games" + clauses
 
 	rows, err := s.db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return fmt.Errorf("query failed: %w", err)
 	}
 	defer rows.Close()
 
-	var games []GameRecord
 	for rows.Next() {
 		var g GameRecord
-		err := rows.Scan(
+		var moveCount int
+		var lastMove sql.NullString
+		dest := []any{
 			&g.GameID, &g.InitialFEN,
 			&g.WhitePlayerID, &g.WhiteType, &g.WhiteLevel, &g.WhiteSearchTime,
 			&g.BlackPlayerID, &g.BlackType, &g.BlackLevel, &g.BlackSearchTime,
-			&g.StartTimeUTC,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
+			&g.StartTimeUTC, &g.Result, &g.Reason, &g.EndTimeUTC,
+		}
+		if activity {
+			dest = append(dest, &moveCount, &lastMove)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		if activity {
+			g.MoveCount = &moveCount
+			if lastMove.Valid {
+				// Aggregates lose the column type, so the driver returns the stored text
+				t, err := parseTimestamp(lastMove.String)
+				if err != nil {
+					return fmt.Errorf("invalid move time for game %s: %w", g.GameID, err)
+				}
+				g.LastMoveUTC = &t
+			}
+		}
+		if err := fn(g); err != nil {
+			return err
 		}
-		games = append(games, g)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration failed: %w", err)
+		return fmt.Errorf("rows iteration failed: %w", err)
 	}
 
-	return games, nil
+	return nil
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+// RateLimitGCInterval is how often expired rate limit counters are removed
+const RateLimitGCInterval = 1 * time.Minute
+
+// RateLimitStorage persists rate limiter counters in the rate_limits table
+// It implements fiber.Storage, keys are namespaced so several limiters can share the table
+type RateLimitStorage struct {
+	store  *Store
+	prefix string
+}
+
+// RateLimiter returns counter storage for the named limiter
+// The first call starts a background job removing expired counters until Close
+func (s *Store) RateLimiter(name string) *RateLimitStorage {
+	s.rateLimitGC.Do(func() {
+		s.wg.Add(1)
+		go s.rateLimitGCLoop()
+	})
+	return &RateLimitStorage{store: s, prefix: name + ":"}
+}
+
+// Get returns the stored value, nil if the key is missing or expired
+func (r *RateLimitStorage) Get(key string) ([]byte, error) {
+	var value []byte
+	query := `SELECT value FROM rate_limits WHERE key = ? AND (expires_at = 0 OR expires_at > ?)`
+	err := r.store.db.QueryRow(query, r.prefix+key, time.Now().UnixMilli()).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Set stores a value, a zero exp keeps it until deleted
+func (r *RateLimitStorage) Set(key string, val []byte, exp time.Duration) error {
+	var expiresAt int64
+	if exp > 0 {
+		expiresAt = time.Now().Add(exp).UnixMilli()
+	}
+	query := `INSERT INTO rate_limits (key, value, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`
+	_, err := r.store.db.Exec(query, r.prefix+key, val, expiresAt)
+	return err
+}
+
+// Delete removes a key
+func (r *RateLimitStorage) Delete(key string) error {
+	_, err := r.store.db.Exec(`DELETE FROM rate_limits WHERE key = ?`, r.prefix+key)
+	return err
+}
+
+// Reset removes all keys of this limiter
+func (r *RateLimitStorage) Reset() error {
+	_, err := r.store.db.Exec(`DELETE FROM rate_limits WHERE substr(key, 1, ?) = ?`, len(r.prefix), r.prefix)
+	return err
+}
+
+// Close is a no-op, the database is owned by the Store
+func (r *RateLimitStorage) Close() error {
+	return nil
+}
+
+// DeleteExpiredRateLimits removes counters past their expiry
+func (s *Store) DeleteExpiredRateLimits() (int64, error) {
+	query := `DELETE FROM rate_limits WHERE expires_at != 0 AND expires_at <= ?`
+	result, err := s.db.Exec(query, time.Now().UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// rateLimitGCLoop periodically removes expired counters until the store closes
+func (s *Store) rateLimitGCLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(RateLimitGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.DeleteExpiredRateLimits(); err != nil {
+				log.Printf("cleanup: failed to delete expired rate limits: %v", err)
+			}
+		}
+	}
+}
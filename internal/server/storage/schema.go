@@ -4,14 +4,14 @@ import "time"
 
 // UserRecord represents a user account in the database
 type UserRecord struct {
-	UserID       string     `db:"user_id"`
-	Username     string     `db:"username"`
-	Email        string     `db:"email"`
-	PasswordHash string     `db:"password_hash"`
-	AccountType  string     `db:"account_type"` // "permanent" or "temp"
-	CreatedAt    time.Time  `db:"created_at"`
-	ExpiresAt    *time.Time `db:"expires_at"` // nil for permanent
-	LastLoginAt  *time.Time `db:"last_login_at"`
+	UserID       string     `db:"user_id" json:"userId"`
+	Username     string     `db:"username" json:"username"`
+	Email        string     `db:"email" json:"email"`
+	PasswordHash string     `db:"password_hash" json:"-"`
+	AccountType  string     `db:"account_type" json:"accountType"` // "permanent" or "temp"
+	CreatedAt    time.Time  `db:"created_at" json:"createdAt"`
+	ExpiresAt    *time.Time `db:"expires_at" json:"expiresAt"` // nil for permanent
+	LastLoginAt  *time.Time `db:"last_login_at" json:"lastLoginAt"`
 }
 
 // SessionRecord represents an active user session
@@ -20,21 +20,30 @@ type SessionRecord struct {
 	UserID    string    `db:"user_id"`
 	CreatedAt time.Time `db:"created_at"`
 	ExpiresAt time.Time `db:"expires_at"`
+	UserAgent string    `db:"user_agent"` // Client that logged in, empty if unknown
+	IPAddress string    `db:"ip_address"` // Client address, from X-Forwarded-For behind a trusted proxy
 }
 
 // GameRecord represents a row in the games table
 type GameRecord struct {
-	GameID          string    `db:"game_id"`
-	InitialFEN      string    `db:"initial_fen"`
-	WhitePlayerID   string    `db:"white_player_id"`
-	WhiteType       int       `db:"white_type"`
-	WhiteLevel      int       `db:"white_level"`
-	WhiteSearchTime int       `db:"white_search_time"`
-	BlackPlayerID   string    `db:"black_player_id"`
-	BlackType       int       `db:"black_type"`
-	BlackLevel      int       `db:"black_level"`
-	BlackSearchTime int       `db:"black_search_time"`
-	StartTimeUTC    time.Time `db:"start_time_utc"`
+	GameID          string     `db:"game_id" json:"gameId"`
+	InitialFEN      string     `db:"initial_fen" json:"initialFen"`
+	WhitePlayerID   string     `db:"white_player_id" json:"whitePlayerId"`
+	WhiteType       int        `db:"white_type" json:"whiteType"`
+	WhiteLevel      int        `db:"white_level" json:"whiteLevel"`
+	WhiteSearchTime int        `db:"white_search_time" json:"whiteSearchTime"`
+	BlackPlayerID   string     `db:"black_player_id" json:"blackPlayerId"`
+	BlackType       int        `db:"black_type" json:"blackType"`
+	BlackLevel      int        `db:"black_level" json:"blackLevel"`
+	BlackSearchTime int        `db:"black_search_time" json:"blackSearchTime"`
+	StartTimeUTC    time.Time  `db:"start_time_utc" json:"startTimeUtc"`
+	Result          string     `db:"result" json:"result"`           // PGN notation, "*" while unfinished or aborted
+	Reason          string     `db:"reason" json:"reason,omitempty"` // Why the game ended, e.g. "checkmate" or "aborted"
+	EndTimeUTC      *time.Time `db:"end_time_utc" json:"endTimeUtc"` // nil while unfinished
+
+	// Activity from the moves table, only filled when requested by GameQuery
+	MoveCount   *int       `json:"moveCount,omitempty"`
+	LastMoveUTC *time.Time `json:"lastMoveUtc,omitempty"` // nil without moves
 }
 
 // MoveRecord represents a row in the moves table
@@ -43,11 +52,21 @@ type MoveRecord struct {
 	GameID       string    `db:"game_id"`
 	MoveNumber   int       `db:"move_number"`
 	MoveUCI      string    `db:"move_uci"`
+	MoveSAN      string    `db:"move_san"` // Empty for moves recorded before SAN was stored
 	FENAfterMove string    `db:"fen_after_move"`
 	PlayerColor  string    `db:"player_color"`
 	MoveTimeUTC  time.Time `db:"move_time_utc"`
 }
 
+// UndoRecord represents a row in the undo_log table, a move taken back by undo or reset
+type UndoRecord struct {
+	UndoID     int64     `db:"undo_id"`
+	GameID     string    `db:"game_id"`
+	MoveNumber int       `db:"move_number"`
+	MoveUCI    string    `db:"move_uci"`
+	UndoneAt   time.Time `db:"undone_at"`
+}
+
 // Schema defines the SQLite database structure
 const Schema = `
 CREATE TABLE IF NOT EXISTS users (
@@ -69,9 +88,11 @@ CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_unique ON users(email) WHERE e
 
 CREATE TABLE IF NOT EXISTS sessions (
 	session_id TEXT PRIMARY KEY,
-	user_id TEXT NOT NULL UNIQUE,
+	user_id TEXT NOT NULL,
 	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 	expires_at DATETIME NOT NULL,
+	user_agent TEXT NOT NULL DEFAULT '',
+	ip_address TEXT NOT NULL DEFAULT '',
 	FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
 );
 
@@ -89,7 +110,10 @@ CREATE TABLE IF NOT EXISTS games (
 	black_type INTEGER NOT NULL,
 	black_level INTEGER NOT NULL DEFAULT 0,
 	black_search_time INTEGER NOT NULL DEFAULT 1000,
-	start_time_utc DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	start_time_utc DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	result TEXT NOT NULL DEFAULT '*',
+	reason TEXT NOT NULL DEFAULT '',
+	end_time_utc DATETIME
 );
 
 CREATE TABLE IF NOT EXISTS moves (
@@ -97,6 +121,7 @@ CREATE TABLE IF NOT EXISTS moves (
 	game_id TEXT NOT NULL,
 	move_number INTEGER NOT NULL,
 	move_uci TEXT NOT NULL,
+	move_san TEXT NOT NULL DEFAULT '',
 	fen_after_move TEXT NOT NULL,
 	player_color TEXT NOT NULL CHECK(player_color IN ('w', 'b')),
 	move_time_utc DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -104,7 +129,42 @@ CREATE TABLE IF NOT EXISTS moves (
 	UNIQUE(game_id, move_number)
 );
 
+CREATE TABLE IF NOT EXISTS undo_log (
+	undo_id INTEGER PRIMARY KEY AUTOINCREMENT,
+	game_id TEXT NOT NULL,
+	move_number INTEGER NOT NULL,
+	move_uci TEXT NOT NULL,
+	undone_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (game_id) REFERENCES games(game_id) ON DELETE CASCADE
+);
+
 CREATE INDEX IF NOT EXISTS idx_moves_game_id ON moves(game_id);
+CREATE INDEX IF NOT EXISTS idx_undo_log_game_id ON undo_log(game_id);
 CREATE INDEX IF NOT EXISTS idx_games_white_player ON games(white_player_id);
 CREATE INDEX IF NOT EXISTS idx_games_black_player ON games(black_player_id);
-`
\ No newline at end of file
+
+CREATE TABLE IF NOT EXISTS rate_limits (
+	key TEXT PRIMARY KEY,
+	value BLOB NOT NULL,
+	expires_at INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON rate_limits(expires_at);
+`
+
+// Migration adds a column introduced after the initial schema to existing databases
+type Migration struct {
+	Table      string
+	Column     string
+	Definition string
+}
+
+// Migrations lists columns added since the initial schema, applied in order by InitDB
+var Migrations = []Migration{
+	{Table: "games", Column: "result", Definition: "TEXT NOT NULL DEFAULT '*'"},
+	{Table: "games", Column: "end_time_utc", Definition: "DATETIME"},
+	{Table: "sessions", Column: "user_agent", Definition: "TEXT NOT NULL DEFAULT ''"},
+	{Table: "sessions", Column: "ip_address", Definition: "TEXT NOT NULL DEFAULT ''"},
+	{Table: "games", Column: "reason", Definition: "TEXT NOT NULL DEFAULT ''"},
+	{Table: "moves", Column: "move_san", Definition: "TEXT NOT NULL DEFAULT ''"},
+}
\ No newline at end of file
@@ -5,7 +5,7 @@ import (
 	"time"
 )
 
-// CreateSession creates or replaces the session for a user (single session per user)
+// CreateSession adds a session for a user, who keeps their other unexpired sessions
 func (s *Store) CreateSession(record SessionRecord) error {
 	tx, err := s.db.Begin()
 	if err != nil {
@@ -13,15 +13,15 @@ func (s *Store) CreateSession(record SessionRecord) error {
 	}
 	defer tx.Rollback()
 
-	// Delete any existing session for this user
-	deleteQuery := `DELETE FROM sessions WHERE user_id = ?`
-	if _, err := tx.Exec(deleteQuery, record.UserID); err != nil {
-		return fmt.Errorf("failed to delete existing session: %w", err)
+	// Drop this user's expired sessions
+	deleteQuery := `DELETE FROM sessions WHERE user_id = ? AND expires_at < ?`
+	if _, err := tx.Exec(deleteQuery, record.UserID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
 	}
 
 	// Insert new session
-	insertQuery := `INSERT INTO sessions (session_id, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)`
-	if _, err := tx.Exec(insertQuery, record.SessionID, record.UserID, record.CreatedAt, record.ExpiresAt); err != nil {
+	insertQuery := `INSERT INTO sessions (session_id, user_id, created_at, expires_at, user_agent, ip_address) VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := tx.Exec(insertQuery, record.SessionID, record.UserID, record.CreatedAt, record.ExpiresAt, record.UserAgent, record.IPAddress); err != nil {
 		return fmt.Errorf("failed to create session: %w", err)
 	}
 
@@ -31,10 +31,10 @@ func (s *Store) CreateSession(record SessionRecord) error {
 // GetSession retrieves a session by ID
 func (s *Store) GetSession(sessionID string) (*SessionRecord, error) {
 	var session SessionRecord
-	query := `SELECT session_id, user_id, created_at, expires_at FROM sessions WHERE session_id = ?`
+	query := `SELECT session_id, user_id, created_at, expires_at, user_agent, ip_address FROM sessions WHERE session_id = ?`
 
 	err := s.db.QueryRow(query, sessionID).Scan(
-		&session.SessionID, &session.UserID, &session.CreatedAt, &session.ExpiresAt,
+		&session.SessionID, &session.UserID, &session.CreatedAt, &session.ExpiresAt, &session.UserAgent, &session.IPAddress,
 	)
 	if err != nil {
 		return nil, err
@@ -42,13 +42,14 @@ func (s *Store) GetSession(sessionID string) (*SessionRecord, error) {
 	return &session, nil
 }
 
-// GetSessionByUserID retrieves the active session for a user
+// GetSessionByUserID retrieves the most recent session for a user
 func (s *Store) GetSessionByUserID(userID string) (*SessionRecord, error) {
 	var session SessionRecord
-	query := `SELECT session_id, user_id, created_at, expires_at FROM sessions WHERE user_id = ?`
+	query := `SELECT session_id, user_id, created_at, expires_at, user_agent, ip_address FROM sessions
+		WHERE user_id = ? ORDER BY created_at DESC LIMIT 1`
 
 	err := s.db.QueryRow(query, userID).Scan(
-		&session.SessionID, &session.UserID, &session.CreatedAt, &session.ExpiresAt,
+		&session.SessionID, &session.UserID, &session.CreatedAt, &session.ExpiresAt, &session.UserAgent, &session.IPAddress,
 	)
 	if err != nil {
 		return nil, err
@@ -63,6 +64,41 @@ func (s *Store) DeleteSession(sessionID string) error {
 	return err
 }
 
+// ListSessionsByUserID returns a user's unexpired sessions, newest first
+func (s *Store) ListSessionsByUserID(userID string) ([]SessionRecord, error) {
+	query := `SELECT session_id, user_id, created_at, expires_at, user_agent, ip_address FROM sessions
+		WHERE user_id = ? AND expires_at > ? ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(query, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []SessionRecord
+	for rows.Next() {
+		var session SessionRecord
+		if err := rows.Scan(
+			&session.SessionID, &session.UserID, &session.CreatedAt, &session.ExpiresAt, &session.UserAgent, &session.IPAddress,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// DeleteUserSession removes a session only if it belongs to the user, reporting whether it existed
+func (s *Store) DeleteUserSession(userID, sessionID string) (bool, error) {
+	query := `DELETE FROM sessions WHERE session_id = ? AND user_id = ?`
+	result, err := s.db.Exec(query, sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
 // DeleteSessionByUserID removes all sessions for a user
 func (s *Store) DeleteSessionByUserID(userID string) error {
 	query := `DELETE FROM sessions WHERE user_id = ?`
@@ -22,6 +22,7 @@ type Store struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
+	rateLimitGC  sync.Once // Starts expired rate limit cleanup on first use
 }
 
 // NewStore creates a new storage instance with async writer
@@ -166,9 +167,132 @@ func (s *Store) InitDB() error {
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	// Bring databases created by older versions up to date
+	for _, m := range Migrations {
+		exists, err := columnExists(tx, m.Table, m.Column)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s.%s: %w", m.Table, m.Column, err)
+		}
+		if exists {
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.Table, m.Column, m.Definition)
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", m.Table, m.Column, err)
+		}
+	}
+
+	if err := allowMultipleSessions(tx); err != nil {
+		return fmt.Errorf("failed to migrate sessions: %w", err)
+	}
+
 	return tx.Commit()
 }
 
+// allowMultipleSessions rebuilds a sessions table from older versions, whose
+// user_id was UNIQUE, so a user can hold one session per device
+// SQLite cannot drop a constraint in place, the rows are copied to a new table
+func allowMultipleSessions(tx *sql.Tx) error {
+	rows, err := tx.Query("PRAGMA index_list(sessions)")
+	if err != nil {
+		return err
+	}
+	unique := false
+	for rows.Next() {
+		var (
+			seq     int
+			name    string
+			isUniq  int
+			origin  string
+			partial int
+		)
+		if err := rows.Scan(&seq, &name, &isUniq, &origin, &partial); err != nil {
+			rows.Close()
+			return err
+		}
+		// Origin "u" is an index created by a UNIQUE column constraint
+		if origin == "u" {
+			unique = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || !unique {
+		return err
+	}
+
+	statements := []string{
+		`CREATE TABLE sessions_new (
+			session_id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			expires_at DATETIME NOT NULL,
+			user_agent TEXT NOT NULL DEFAULT '',
+			ip_address TEXT NOT NULL DEFAULT '',
+			FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+		)`,
+		`INSERT INTO sessions_new (session_id, user_id, created_at, expires_at, user_agent, ip_address)
+			SELECT session_id, user_id, created_at, expires_at, user_agent, ip_address FROM sessions`,
+		`DROP TABLE sessions`,
+		`ALTER TABLE sessions_new RENAME TO sessions`,
+		`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions(expires_at)`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnExists checks the table definition for a column within a transaction
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// IntegrityCheck runs SQLite's integrity check and returns the reported problems, empty when healthy
+func (s *Store) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("integrity check failed: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	return problems, rows.Err()
+}
+
 // DeleteDB removes the database file
 func (s *Store) DeleteDB() error {
 	// Close connection first
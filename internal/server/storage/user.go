@@ -100,6 +100,46 @@ func (s *Store) CreateUser(record UserRecord) error {
 	return tx.Commit()
 }
 
+// ImportUsers creates users in a single transaction, skipping any whose username
+// or email is taken, including by an earlier record in the batch
+// Returns which records were created, in dry-run mode nothing is committed
+func (s *Store) ImportUsers(records []UserRecord, dryRun bool) ([]bool, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO users (
+		user_id, username, email, password_hash, account_type, created_at, expires_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	created := make([]bool, len(records))
+	for i, record := range records {
+		exists, err := s.userExists(tx, record.Username, record.Email)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+
+		_, err = tx.Exec(query,
+			record.UserID, record.Username, record.Email,
+			record.PasswordHash, record.AccountType, record.CreatedAt, record.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert user %s: %w", record.Username, err)
+		}
+		created[i] = true
+	}
+
+	if dryRun {
+		return created, nil
+	}
+	return created, tx.Commit()
+}
+
 // DeleteUserByID removes a user by ID (synchronous, for replacement logic)
 func (s *Store) DeleteUserByID(userID string) error {
 	query := `DELETE FROM users WHERE user_id = ?`
@@ -356,6 +356,10 @@ function updateTurnIndicator(state, turn) {
                 status = 'draw';
                 tooltipText = 'Draw';
                 break;
+            case 'aborted':
+                status = 'aborted';
+                tooltipText = 'Aborted';
+                break;
             default:
                 status = 'unknown';
                 tooltipText = 'Game Over';
@@ -493,8 +497,12 @@ async function startNewGame() {
         requestBody.fen = startingFEN;
     }
 
+    // The server plays the computer's first move before responding
+    const query = gameState.isPlayerWhite ? '' : '?waitFirstMove=true';
+    if (!gameState.isPlayerWhite) lockBoard();
+
     try {
-        const response = await authFetch(`${gameState.apiUrl}/api/v1/games`, {
+        const response = await authFetch(`${gameState.apiUrl}/api/v1/games${query}`, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify(requestBody)
@@ -511,9 +519,15 @@ async function startNewGame() {
         initializeBoard();
         updateGameDisplay(game);
         document.getElementById('undo-btn').disabled = true;
-        if (!gameState.isPlayerWhite) triggerComputerMove();
+        if (game.state === 'pending') {
+            // The move outlasted the wait, follow it by polling
+            startPolling();
+        } else if (!gameState.isPlayerWhite) {
+            unlockBoard();
+        }
 
     } catch (error) {
+        if (!gameState.isPlayerWhite) unlockBoard();
         if (error.message === 'Failed to fetch') {
             handleApiError('create game', error);
         } else {
@@ -899,7 +913,7 @@ function markMatedKing(game) {
 }
 
 function isGameOver(state) {
-    return ['white wins', 'black wins', 'stalemate', 'draw'].includes(state);
+    return ['white wins', 'black wins', 'stalemate', 'draw', 'aborted'].includes(state);
 }
 
 function handleApiError(action, error, response = null) {
@@ -147,6 +147,22 @@ STATUS=$(api_request GET "$BASE_URL/health" -o /dev/null -w "%{http_code}")
 assert_status 200 "$STATUS" "Health endpoint"
 assert_json_field "$RESPONSE" '.status' "healthy" "Health status"
 
+test_case "1.1a: Version"
+RESPONSE=$(api_request GET "$API_URL/version")
+assert_json_field "$RESPONSE" '.api' "v1" "API version"
+if echo "$RESPONSE" | jq -r '.engine' 2>/dev/null | grep -qi "stockfish"; then
+    echo -e "${GREEN}  ✓ Engine identified: $(echo "$RESPONSE" | jq -r '.engine')${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Engine name missing${NC}"
+    ((FAIL++))
+fi
+
+test_case "1.1b: Metrics"
+RESPONSE=$(api_request GET "$BASE_URL/metrics")
+assert_json_field "$RESPONSE" '.evalCache.enabled' "true" "Evaluation cache enabled by default"
+assert_json_field "$RESPONSE" '.evalCache.size' "4096" "Evaluation cache size"
+
 test_case "1.2: Create Human vs Human Game"
 RESPONSE=$(api_request POST "$API_URL/games" \
     -H "Content-Type: application/json" \
@@ -177,6 +193,62 @@ if [ "$HVH_ID" != "null" ] && [ -n "$HVH_ID" ]; then
         -d '{"move": "e2e5"}')
     assert_status 400 "$STATUS" "Invalid move e2e5 rejected"
 
+    test_case "1.4a0: Move Piece Of Side Not To Move"
+    RESPONSE=$(api_request POST "$API_URL/games/$HVH_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "d2d4"}')
+    assert_json_field "$RESPONSE" '.details' "not your turn to move this piece: d2 holds a white piece, black to move" "Wrong side reported"
+
+    test_case "1.4a0a: Promotion Piece Checks"
+    RESPONSE=$(api_request POST "$API_URL/games" \
+        -H "Content-Type: application/json" \
+        -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "4k3/P7/8/8/8/8/8/4K3 w - - 0 1"}')
+    PROMO_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+    RESPONSE=$(api_request POST "$API_URL/games/$PROMO_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "a7a8"}')
+    assert_json_field "$RESPONSE" '.details' "a7a8 promotes a pawn and needs a promotion piece (q, r, b or n), e.g. a7a8q" "Missing promotion piece reported"
+    RESPONSE=$(api_request POST "$API_URL/games/$PROMO_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e1e2q"}')
+    assert_json_field "$RESPONSE" '.details' "e1e2q is not a promotion, only a pawn reaching the last rank takes a promotion piece" "Superfluous promotion piece reported"
+    RESPONSE=$(api_request POST "$API_URL/games/$PROMO_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "a7a8n"}')
+    assert_json_field "$RESPONSE" '.fen' "N3k3/8/8/8/8/8/8/4K3 b - - 0 1" "Underpromotion applied"
+    api_request DELETE "$API_URL/games/$PROMO_ID" > /dev/null
+
+    test_case "1.4a: Move With Stale Expected Move Count"
+    STATUS=$(api_request POST "$API_URL/games/$HVH_ID/moves" \
+        -o /dev/null -w "%{http_code}" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e7e5", "expectedMoveCount": 0}')
+    assert_status 409 "$STATUS" "Stale expectedMoveCount rejected"
+
+    test_case "1.4b: Move With Current Expected Move Count"
+    STATUS=$(api_request POST "$API_URL/games/$HVH_ID/moves" \
+        -o /dev/null -w "%{http_code}" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e7e5", "expectedMoveCount": 1}')
+    assert_status 200 "$STATUS" "Matching expectedMoveCount accepted"
+
+    test_case "1.4c: Batch Move With Malformed Element"
+    RESPONSE=$(api_request POST "$API_URL/games/$HVH_ID/moves/batch" \
+        -H "Content-Type: application/json" \
+        -d '{"moves": ["g1f3", "zz"]}')
+    assert_json_field "$RESPONSE" '.details' "moves[1] is not valid UCI" "Element error names index"
+
+    test_case "1.4d: Batch Move"
+    RESPONSE=$(api_request POST "$API_URL/games/$HVH_ID/moves/batch" \
+        -H "Content-Type: application/json" \
+        -d '{"moves": ["g1f3", "b8c6"]}')
+    assert_json_field "$RESPONSE" '.moves | length' "4" "Both moves applied"
+
+    test_case "1.4e: Get Turn"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/turn")
+    assert_json_field "$RESPONSE" '.moveCount' "4" "Move count reported"
+    assert_json_field "$RESPONSE" '.turn' "w" "White to move"
+
     test_case "1.5: Get ASCII Board"
     RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/board")
     STATUS=$(api_request GET "$API_URL/games/$HVH_ID/board" -o /dev/null -w "%{http_code}")
@@ -190,6 +262,56 @@ if [ "$HVH_ID" != "null" ] && [ -n "$HVH_ID" ]; then
         ((FAIL++))
     fi
 
+    test_case "1.5a: Analyze Game"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/analysis?depth=6")
+    assert_json_field "$RESPONSE" '.plies | length' "4" "One analysis entry per move"
+
+    test_case "1.5b: Get Hint"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/hint?strength=5")
+    assert_json_field "$RESPONSE" '.playerColor' "w" "Hint for side to move"
+
+    test_case "1.5b1: Grade Move"
+    RESPONSE=$(api_request POST "$API_URL/games/$HVH_ID/grade" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "f1c4"}')
+    assert_json_field "$RESPONSE" '.grade | IN("best","good","inaccuracy","mistake","blunder")' "true" "Move graded"
+    STATUS=$(api_request POST "$API_URL/games/$HVH_ID/grade" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e1e3"}' -o /dev/null -w "%{http_code}")
+    assert_status 400 "$STATUS" "Illegal candidate rejected"
+
+    test_case "1.5c: Hint With Invalid Strength"
+    STATUS=$(api_request GET "$API_URL/games/$HVH_ID/hint?strength=25" -o /dev/null -w "%{http_code}")
+    assert_status 400 "$STATUS" "Strength above 20 rejected"
+
+    test_case "1.5d: Board Without Coordinates"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/board?coords=false&empty=-")
+    assert_json_field "$RESPONSE" '.board | split("\n") | length' "8" "Eight rows without labels"
+    assert_json_field "$RESPONSE" '.board | split("\n")[4]' "- - - - P - - -" "Custom empty square character"
+
+    test_case "1.5e: Board FEN Fields"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/board")
+    assert_json_field "$RESPONSE" '"\(.castling) \(.enPassant) \(.halfmove) \(.fullmove)"' "KQkq - 2 3" "Castling, en passant and counters parsed"
+
+    test_case "1.5e1: Board Status"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/board?status=true")
+    assert_json_field "$RESPONSE" '"\(.inCheck) \(.gameOver)"' "false false" "Status fields returned"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID/board")
+    assert_json_field "$RESPONSE" 'has("inCheck")' "false" "Status omitted by default"
+    STATUS=$(api_request GET "$API_URL/games/$HVH_ID/board?status=maybe" -o /dev/null -w "%{http_code}")
+    assert_status 400 "$STATUS" "Invalid status rejected"
+
+    test_case "1.5f: Game Field Selection"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID?fields=fen,turn,state")
+    assert_json_field "$RESPONSE" 'keys | join(",")' "fen,state,turn" "Only selected fields returned"
+    STATUS=$(api_request GET "$API_URL/games/$HVH_ID?fields=fen,bogus" -o /dev/null -w "%{http_code}")
+    assert_status 400 "$STATUS" "Unknown field rejected"
+
+    test_case "1.5g: Move Window"
+    RESPONSE=$(api_request GET "$API_URL/games/$HVH_ID?movesFrom=1&movesLimit=2")
+    assert_json_field "$RESPONSE" '.moves | join(",")' "e7e5,g1f3" "Moves sliced to the window"
+    assert_json_field "$RESPONSE" '.moveCount' "4" "Total move count reported"
+
     test_case "1.6: Delete Game"
     STATUS=$(api_request DELETE "$API_URL/games/$HVH_ID" -o /dev/null -w "%{http_code}")
     assert_status 204 "$STATUS" "Delete game"
@@ -395,6 +517,18 @@ test_case "5.2: Invalid UUID Format"
 STATUS=$(api_request GET "$API_URL/games/not-a-uuid" -o /dev/null -w "%{http_code}")
 assert_status 400 "$STATUS" "Invalid UUID rejected"
 
+test_case "5.2a: Invalid UUID On Every Game Endpoint"
+# Body endpoints get the same error as bodiless ones, the ID is checked first
+for ENDPOINT in "PUT /players" "GET " "DELETE " "POST /moves" "POST /moves/batch" "POST /undo" \
+    "POST /reset" "POST /rematch" "POST /recover" "POST /abort" "GET /turn" "GET /players" \
+    "GET /board" "GET /scoresheet" "GET /analysis" "GET /hint" "POST /grade" "GET /search-stream"; do
+    METHOD=${ENDPOINT%% *}
+    SUFFIX=${ENDPOINT#* }
+    RESPONSE=$(api_request "$METHOD" "$API_URL/games/not-a-uuid$SUFFIX" \
+        -H "Content-Type: application/json" -d '{}')
+    assert_json_field "$RESPONSE" '.details' "game ID must be a valid UUID" "$METHOD /games/not-a-uuid$SUFFIX"
+done
+
 test_case "5.3: Invalid JSON Body"
 STATUS=$(api_request POST "$API_URL/games" \
     -o /dev/null -w "%{http_code}" \
@@ -469,6 +603,27 @@ else
     ((SKIP++))
 fi
 
+test_case "5.7: Recover Game That Is Not Stuck"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+STATUS=$(api_request POST "$API_URL/games/$GAME_ID/recover" -o /dev/null -w "%{http_code}")
+assert_status 400 "$STATUS" "Recovering an ongoing game rejected"
+[ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ] && api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+
+test_case "5.8: Abort Game"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/abort")
+assert_json_field "$RESPONSE" '.state' "aborted" "Game aborted"
+assert_json_field "$RESPONSE" '.reason' "aborted" "Abort reason reported"
+RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/abort")
+assert_json_field "$RESPONSE" '.code' "GAME_OVER" "Aborting twice rejected"
+[ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ] && api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+
 # ==============================================================================
 print_header "SECTION 6: Rate Limiting (Dev Mode)"
 # ==============================================================================
@@ -521,6 +676,34 @@ STATUS2=$(api_request GET "$API_URL/games/$UUID2" -o /dev/null -w "%{http_code}"
 assert_status 404 "$STATUS1" "First IP request"
 assert_status 404 "$STATUS2" "Different IP not limited"
 
+test_case "6.3: Concurrent Long-Poll Limit"
+sleep 1.1
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}')
+POLL_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$POLL_ID" != "null" ] && [ -n "$POLL_ID" ]; then
+    # Hold the default 10 long-polls open, the next one is refused
+    for i in {1..10}; do
+        curl -s -o /dev/null "$API_URL/games/$POLL_ID?wait=true&moveCount=0" &
+    done
+    sleep 0.5
+    STATUS=$(api_request GET "$API_URL/games/$POLL_ID?wait=true&moveCount=0" -o /dev/null -w "%{http_code}")
+    assert_status 429 "$STATUS" "Long-poll over the per-IP connection limit"
+
+    # A move completes the open polls and frees their slots
+    api_request POST "$API_URL/games/$POLL_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e2e4"}' > /dev/null
+    wait
+    STATUS=$(api_request GET "$API_URL/games/$POLL_ID?wait=true&moveCount=0" -o /dev/null -w "%{http_code}")
+    assert_status 200 "$STATUS" "Slots released once long-polls complete"
+    api_request DELETE "$API_URL/games/$POLL_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping connection limit test${NC}"
+    ((SKIP++))
+fi
+
 # ==============================================================================
 print_header "SECTION 7: Advanced Scenarios"
 # ==============================================================================
@@ -533,19 +716,27 @@ RESPONSE=$(api_request POST "$API_URL/games" \
 UNDO_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
 
 if [ "$UNDO_ID" != "null" ] && [ -n "$UNDO_ID" ]; then
-    # Make 3 moves
+    # Make 4 moves
     api_request POST "$API_URL/games/$UNDO_ID/moves" -H "Content-Type: application/json" -d '{"move": "e2e4"}' > /dev/null
     api_request POST "$API_URL/games/$UNDO_ID/moves" -H "Content-Type: application/json" -d '{"move": "cccc"}' > /dev/null
     wait_for_state "$UNDO_ID" "!pending"
     api_request POST "$API_URL/games/$UNDO_ID/moves" -H "Content-Type: application/json" -d '{"move": "g1f3"}' > /dev/null
+    api_request POST "$API_URL/games/$UNDO_ID/moves" -H "Content-Type: application/json" -d '{"move": "cccc"}' > /dev/null
+    wait_for_state "$UNDO_ID" "!pending"
+
+    # Smart undo of 1 also takes back the computer's reply
+    RESPONSE=$(api_request POST "$API_URL/games/$UNDO_ID/undo" \
+        -H "Content-Type: application/json" \
+        -d '{"count": 1}')
+    assert_json_field "$RESPONSE" '.moves | length' "2" "Smart undo returned to human's turn"
 
-    # Undo 2 moves
+    # Undo 1 more move exactly
     RESPONSE=$(api_request POST "$API_URL/games/$UNDO_ID/undo" \
         -H "Content-Type: application/json" \
-        -d '{"count": 2}')
+        -d '{"count": 1, "smart": false}')
     MOVES_COUNT=$(echo "$RESPONSE" | jq -r '.moves | length' 2>/dev/null)
     if [ "$MOVES_COUNT" = "1" ]; then
-        echo -e "${GREEN}  ✓ Successfully undid 2 moves${NC}"
+        echo -e "${GREEN}  ✓ Successfully undid 3 moves${NC}"
         ((PASS++))
     else
         echo -e "${RED}  ✗ Expected 1 move remaining, got $MOVES_COUNT${NC}"
@@ -559,6 +750,108 @@ else
     ((SKIP++))
 fi
 
+test_case "7.1a: Reset Game"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}')
+RESET_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+
+if [ "$RESET_ID" != "null" ] && [ -n "$RESET_ID" ]; then
+    api_request POST "$API_URL/games/$RESET_ID/moves/batch" -H "Content-Type: application/json" \
+        -d '{"moves": ["f2f3", "e7e5", "g2g4", "d8h4"]}' > /dev/null
+    RESPONSE=$(api_request GET "$API_URL/games/$RESET_ID/board?status=true")
+    assert_json_field "$RESPONSE" '"\(.inCheck) \(.gameOver) \(.reason)"' "true true checkmate" "Board status reports the mate"
+
+    RESPONSE=$(api_request POST "$API_URL/games/$RESET_ID/reset")
+    MOVES_COUNT=$(echo "$RESPONSE" | jq -r '.moves | length' 2>/dev/null)
+    STATE=$(echo "$RESPONSE" | jq -r '.state' 2>/dev/null)
+    if [ "$MOVES_COUNT" = "0" ] && [ "$STATE" = "ongoing" ]; then
+        echo -e "${GREEN}  ✓ Finished game reset to starting position${NC}"
+        ((PASS++))
+    else
+        echo -e "${RED}  ✗ Expected 0 moves and ongoing, got $MOVES_COUNT and $STATE${NC}"
+        ((FAIL++))
+    fi
+
+    api_request DELETE "$API_URL/games/$RESET_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping reset test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.1b: Rematch With Swapped Colors"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 2, "level": 3}}')
+REMATCH_SRC=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+
+if [ "$REMATCH_SRC" != "null" ] && [ -n "$REMATCH_SRC" ]; then
+    STATUS=$(api_request POST "$API_URL/games/$REMATCH_SRC/rematch" -o /dev/null -w "%{http_code}")
+    assert_status 400 "$STATUS" "Rematch rejected for ongoing game"
+
+    api_request POST "$API_URL/games/$REMATCH_SRC/moves" -H "Content-Type: application/json" -d '{"move": "f2f3"}' > /dev/null
+    api_request POST "$API_URL/games/$REMATCH_SRC/moves" -H "Content-Type: application/json" -d '{"move": "cccc"}' > /dev/null
+    wait_for_state "$REMATCH_SRC" "!pending"
+    api_request POST "$API_URL/games/$REMATCH_SRC/moves" -H "Content-Type: application/json" -d '{"move": "g2g4"}' > /dev/null
+    api_request POST "$API_URL/games/$REMATCH_SRC/moves" -H "Content-Type: application/json" -d '{"move": "cccc"}' > /dev/null
+    wait_for_state "$REMATCH_SRC" "!pending"
+    STATE=$(api_request GET "$API_URL/games/$REMATCH_SRC" | jq -r '.state' 2>/dev/null)
+
+    if [ "$STATE" != "ongoing" ]; then
+        RESPONSE=$(api_request POST "$API_URL/games/$REMATCH_SRC/rematch")
+        PREVIOUS=$(echo "$RESPONSE" | jq -r '.previousGameId' 2>/dev/null)
+        WHITE_TYPE=$(echo "$RESPONSE" | jq -r '.players.white.type' 2>/dev/null)
+        if [ "$PREVIOUS" = "$REMATCH_SRC" ] && [ "$WHITE_TYPE" = "2" ]; then
+            echo -e "${GREEN}  ✓ Rematch linked with colors swapped${NC}"
+            ((PASS++))
+        else
+            echo -e "${RED}  ✗ Expected previousGameId and computer as white, got $PREVIOUS and $WHITE_TYPE${NC}"
+            ((FAIL++))
+        fi
+        api_request DELETE "$API_URL/games/$(echo "$RESPONSE" | jq -r '.gameId')" > /dev/null
+    else
+        echo -e "${YELLOW}  ⊘ Engine avoided mate, skipping rematch check${NC}"
+        ((SKIP++))
+    fi
+
+    api_request DELETE "$API_URL/games/$REMATCH_SRC" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping rematch test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.1c: Auto-Start Computer First Move"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 2, "level": 1, "searchTime": 100}, "black": {"type": 1}, "autoStart": true}')
+AUTO_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+STATE=$(echo "$RESPONSE" | jq -r '.state' 2>/dev/null)
+if [ "$STATE" = "pending" ]; then
+    echo -e "${GREEN}  ✓ Game returned pending without a trigger${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Expected pending, got $STATE${NC}"
+    ((FAIL++))
+fi
+if [ "$AUTO_ID" != "null" ] && [ -n "$AUTO_ID" ]; then
+    wait_for_state "$AUTO_ID" "!pending"
+    api_request DELETE "$API_URL/games/$AUTO_ID" > /dev/null
+fi
+
+test_case "7.1d: Create Waiting For First Computer Move"
+RESPONSE=$(api_request POST "$API_URL/games?waitFirstMove=true" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 2, "level": 1, "searchTime": 100}, "black": {"type": 1}}')
+WAIT_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+assert_json_field "$RESPONSE" '"\(.state) \(.moves | length) \(.lastMove.playerColor)"' "ongoing 1 w" "First move played before the response"
+STATUS=$(api_request POST "$API_URL/games?waitFirstMove=maybe" -o /dev/null -w "%{http_code}" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 2}, "black": {"type": 1}}')
+assert_status 400 "$STATUS" "Invalid waitFirstMove rejected"
+if [ "$WAIT_ID" != "null" ] && [ -n "$WAIT_ID" ]; then
+    api_request DELETE "$API_URL/games/$WAIT_ID" > /dev/null
+fi
+
 test_case "7.2: Custom FEN Position"
 CUSTOM_FEN="r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 4 4"
 RESPONSE=$(api_request POST "$API_URL/games" \
@@ -577,6 +870,151 @@ fi
 GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
 [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ] && api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
 
+test_case "7.2a: Scoresheet With Black Moving First"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 3 3"}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    api_request POST "$API_URL/games/$GAME_ID/moves/batch" \
+        -H "Content-Type: application/json" \
+        -d '{"moves": ["g8f6", "f3e5"]}' > /dev/null
+    RESPONSE=$(api_request GET "$API_URL/games/$GAME_ID/scoresheet")
+    assert_json_field "$RESPONSE" '.[0] | "\(.number) \(.white // "...") \(.black)"' "3 ... Nf6" "First line holds Black's move only"
+    assert_json_field "$RESPONSE" '.[1] | "\(.number) \(.white)"' "4 Nxe5" "Next line numbered from the FEN"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping scoresheet test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.3: Drawn Starting Position"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "8/8/4k3/8/8/2B5/8/4K3 w - - 0 1"}')
+assert_json_field "$RESPONSE" '.state' "draw" "King and bishop vs king starts drawn"
+assert_json_field "$RESPONSE" '.reason' "insufficient_material" "Draw reason reported"
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+[ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ] && api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+
+test_case "7.3a: Stalemate Detection"
+# Qe7-f7 leaves the black king on h8 without a move but not in check
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "7k/4Q3/6K1/8/8/8/8/8 w - - 0 1"}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e7f7"}')
+    assert_json_field "$RESPONSE" '.state' "stalemate" "Stalemate not reported as checkmate"
+    assert_json_field "$RESPONSE" '.reason' "stalemate" "Stalemate reason reported"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping stalemate test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.3e: Threefold Repetition Across Undo"
+# Both knights return home twice, the starting position occurs a third time
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/moves/batch" \
+        -H "Content-Type: application/json" \
+        -d '{"moves": ["g1f3", "g8f6", "f3g1", "f6g8", "g1f3", "g8f6", "f3g1", "f6g8"]}')
+    assert_json_field "$RESPONSE" '.state' "draw" "Threefold repetition drawn"
+    assert_json_field "$RESPONSE" '.reason' "threefold_repetition" "Repetition reason reported"
+
+    # Taking back the repeating move leaves the position twice, play resumes
+    RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/undo" \
+        -H "Content-Type: application/json" \
+        -d '{"count": 1}')
+    assert_json_field "$RESPONSE" '.state' "ongoing" "Undo past repetition reopens game"
+
+    # Playing the move again repeats the position a third time once more
+    RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "f6g8"}')
+    assert_json_field "$RESPONSE" '.state' "draw" "Replayed move draws again"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping repetition test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.3f: Fifty-Move Rule"
+# The halfmove clock stands at 99, a quiet rook move reaches 100
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "4k3/8/8/8/8/8/8/R3K3 w - - 99 80"}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "a1a2"}')
+    assert_json_field "$RESPONSE" '"\(.state) \(.reason)"' "draw fifty_move_rule" "Fifty-move rule drawn"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping fifty-move test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.3b: Checkmate Detection"
+# Qe7-e8 mates the black king on h8
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "7k/4Q3/6K1/8/8/8/8/8 w - - 0 1"}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    RESPONSE=$(api_request POST "$API_URL/games/$GAME_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e7e8"}')
+    assert_json_field "$RESPONSE" '.state' "white wins" "Checkmate not reported as stalemate"
+    assert_json_field "$RESPONSE" '.reason' "checkmate" "Checkmate reason reported"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping checkmate test${NC}"
+    ((SKIP++))
+fi
+
+test_case "7.3d: Create From Checkmate Position"
+# Black to move is already mated by the queen on e8
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "4Q2k/8/6K1/8/8/8/8/8 b - - 0 1"}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+assert_json_field "$RESPONSE" '.state' "white wins" "Terminal state in create response"
+assert_json_field "$RESPONSE" '.reason' "checkmate" "Checkmate reason in create response"
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    STATUS=$(api_request POST "$API_URL/games/$GAME_ID/moves" \
+        -o /dev/null -w "%{http_code}" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "h8g8"}')
+    assert_status 400 "$STATUS" "No moves in a finished game"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+fi
+
+test_case "7.3c: Computer Delivers Checkmate"
+# Black computer to move mates with Ra8-a1 on the back rank
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 2, "level": 20, "searchTime": 500}, "fen": "r5k1/8/8/8/8/8/5PPP/6K1 b - - 0 1", "autoStart": true}')
+GAME_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$GAME_ID" != "null" ] && [ -n "$GAME_ID" ]; then
+    wait_for_state "$GAME_ID" "black wins" 30
+    RESPONSE=$(api_request GET "$API_URL/games/$GAME_ID")
+    assert_json_field "$RESPONSE" '.moves[0]' "a8a1" "Mate in one played"
+    assert_json_field "$RESPONSE" '.state' "black wins" "Mating side recorded as winner"
+    assert_json_field "$RESPONSE" '.reason' "checkmate" "Checkmate reason reported"
+    api_request DELETE "$API_URL/games/$GAME_ID" > /dev/null
+else
+    echo -e "${YELLOW}  ⊘ Skipping computer checkmate test${NC}"
+    ((SKIP++))
+fi
+
 # ==============================================================================
 print_header "SECTION 8: Player Configuration"
 # ==============================================================================
@@ -590,6 +1028,11 @@ if [ "$CONFIG_ID" != "null" ] && [ -n "$CONFIG_ID" ]; then
     assert_json_field "$RESPONSE" '.players.black.type' "2" "Black is computer"
     echo "  Game ID: $CONFIG_ID"
 
+    test_case "8.1a: Get Players"
+    RESPONSE=$(api_request GET "$API_URL/games/$CONFIG_ID/players")
+    assert_json_field "$RESPONSE" '.black.level' "10" "Computer level returned"
+    assert_json_field "$RESPONSE" '.black.searchTime' "500" "Computer search time returned"
+
     test_case "8.2: Change Players Mid-Game"
     # Make a move first
     api_request POST "$API_URL/games/$CONFIG_ID/moves" \
@@ -613,6 +1056,39 @@ else
     SKIP=$((SKIP + 3))
 fi
 
+test_case "8.3: Configure Players Between Moves"
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}')
+TURN_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+if [ "$TURN_ID" != "null" ] && [ -n "$TURN_ID" ]; then
+    api_request POST "$API_URL/games/$TURN_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d '{"move": "e2e4"}' > /dev/null
+
+    # A configuration made against a stale view of the game is refused
+    STATUS=$(curl -s -o /dev/null -w "%{http_code}" -X PUT "$API_URL/games/$TURN_ID/players" \
+        -H "Content-Type: application/json" \
+        -d '{"white": {"type": 1}, "black": {"type": 2, "searchTime": 100}, "expectedMoveCount": 0}')
+    assert_status 409 "$STATUS" "Stale expectedMoveCount rejected with conflict"
+
+    # Handing black, the side to move, to the computer starts its move
+    RESPONSE=$(api_request PUT "$API_URL/games/$TURN_ID/players" \
+        -H "Content-Type: application/json" \
+        -d '{"white": {"type": 1}, "black": {"type": 2, "searchTime": 100}, "expectedMoveCount": 1}')
+    assert_json_field "$RESPONSE" '.turn' "b" "Configuration keeps the side to move"
+    assert_json_field "$RESPONSE" '.state' "pending" "Computer move started for the side to move"
+
+    sleep 1
+    RESPONSE=$(api_request GET "$API_URL/games/$TURN_ID")
+    assert_json_field "$RESPONSE" '.moves | length' "2" "Computer replied without cccc"
+
+    api_request DELETE "$API_URL/games/$TURN_ID" > /dev/null
+else
+    echo -e "${RED}  ✗ Failed to create game for turn consistency test${NC}"
+    ((FAIL++))
+fi
+
 # ==============================================================================
 print_header "SECTION 9: Security Hardening Tests"
 # ==============================================================================
@@ -673,7 +1149,7 @@ RESPONSE=$(api_request POST "$API_URL/games" \
     -H "Content-Type: application/json" \
     -d '{"white": {"type": 1}, "black": {"type": 2, "level": 100, "searchTime": 100}}')
 ERROR_MSG=$(echo "$RESPONSE" | jq -r '.details' 2>/dev/null)
-if [[ "$ERROR_MSG" == *"Level must be at most 20"* ]]; then
+if [[ "$ERROR_MSG" == *"black.level must be at most 20"* ]]; then
     echo -e "${GREEN}  ✓ Invalid AI level rejected${NC}"
     ((PASS++))
 else
@@ -687,7 +1163,7 @@ RESPONSE=$(api_request POST "$API_URL/games" \
     -H "Content-Type: application/json" \
     -d '{"white": {"type": 2, "searchTime": -1000}, "black": {"type": 1}}')
 ERROR_MSG=$(echo "$RESPONSE" | jq -r '.details' 2>/dev/null)
-if [[ "$ERROR_MSG" == *"SearchTime must be at least 100"* ]]; then
+if [[ "$ERROR_MSG" == *"white.searchTime must be at least 100"* ]]; then
     echo -e "${GREEN}  ✓ Invalid search time rejected${NC}"
     ((PASS++))
 else
@@ -701,7 +1177,7 @@ RESPONSE=$(api_request POST "$API_URL/games" \
     -H "Content-Type: application/json" \
     -d '{"white": {"type": 2, "searchTime": 50}, "black": {"type": 1}}')
 ERROR_MSG=$(echo "$RESPONSE" | jq -r '.details' 2>/dev/null)
-if [[ "$ERROR_MSG" == *"SearchTime must be at least 100"* ]]; then
+if [[ "$ERROR_MSG" == *"white.searchTime must be at least 100"* ]]; then
     echo -e "${GREEN}  ✓ Search time below minimum rejected${NC}"
     ((PASS++))
 else
@@ -710,6 +1186,49 @@ else
     ((FAIL++))
 fi
 
+# Search time handicap reported as effective time
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 2, "searchTime": 1000, "searchTimeHandicapPercent": 50}}')
+EFFECTIVE=$(echo "$RESPONSE" | jq -r '.players.black.effectiveSearchTime' 2>/dev/null)
+if [ "$EFFECTIVE" = "500" ]; then
+    echo -e "${GREEN}  ✓ Handicap applied to search time${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Expected effective search time 500, got $EFFECTIVE${NC}"
+    show_error "$RESPONSE"
+    ((FAIL++))
+fi
+HANDICAP_ID=$(echo "$RESPONSE" | jq -r '.gameId' 2>/dev/null)
+[ "$HANDICAP_ID" != "null" ] && [ -n "$HANDICAP_ID" ] && api_request DELETE "$API_URL/games/$HANDICAP_ID" > /dev/null
+
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 2, "searchTimeHandicapPercent": 5}}')
+ERROR_MSG=$(echo "$RESPONSE" | jq -r '.details' 2>/dev/null)
+if [[ "$ERROR_MSG" == *"black.searchTimeHandicapPercent must be at least 10"* ]]; then
+    echo -e "${GREEN}  ✓ Handicap out of range rejected${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Handicap out of range not rejected${NC}"
+    show_error "$RESPONSE"
+    ((FAIL++))
+fi
+
+# Engine options not on the server allowlist
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 2, "engineOptions": {"Debug Log File": "/tmp/x"}}}')
+ERROR_MSG=$(echo "$RESPONSE" | jq -r '.error' 2>/dev/null)
+if [[ "$ERROR_MSG" == *"is not allowed"* ]]; then
+    echo -e "${GREEN}  ✓ Unlisted engine option rejected${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Unlisted engine option not rejected${NC}"
+    show_error "$RESPONSE"
+    ((FAIL++))
+fi
+
 test_case "9.3: Move Format Validation"
 # Create test game
 RESPONSE=$(api_request POST "$API_URL/games" \
@@ -780,6 +1299,46 @@ else
     ((FAIL++))
 fi
 
+# Black in check with white to move cannot arise in a game
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "4k3/8/8/8/8/8/8/4R1K1 w - - 0 1"}')
+ERROR_MSG=$(echo "$RESPONSE" | jq -r '.error' 2>/dev/null)
+if [[ "$ERROR_MSG" == *"black is in check but it is white to move"* ]]; then
+    echo -e "${GREEN}  ✓ Side not to move in check rejected${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Side not to move in check not rejected: $ERROR_MSG${NC}"
+    ((FAIL++))
+fi
+
+# Two white kings pass the format check but not the position check
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "4k3/8/8/8/8/8/8/K3K3 w - - 0 1"}')
+ERROR_MSG=$(echo "$RESPONSE" | jq -r '.error' 2>/dev/null)
+CODE=$(echo "$RESPONSE" | jq -r '.code' 2>/dev/null)
+if [[ "$ERROR_MSG" == *"white has 2 kings"* ]] && [ "$CODE" = "INVALID_FEN" ]; then
+    echo -e "${GREEN}  ✓ Extra king rejected with INVALID_FEN${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Extra king not rejected: $ERROR_MSG${NC}"
+    ((FAIL++))
+fi
+
+# An en passant square without the double pushed pawn behind it
+RESPONSE=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}, "fen": "4k3/8/8/8/8/8/8/4K3 w - e6 0 1"}')
+ERROR_MSG=$(echo "$RESPONSE" | jq -r '.error' 2>/dev/null)
+if [[ "$ERROR_MSG" == *"invalid FEN"* ]] || [ "$(echo "$RESPONSE" | jq -r '.fen' 2>/dev/null)" = "4k3/8/8/8/8/8/8/4K3 w - - 0 1" ]; then
+    echo -e "${GREEN}  ✓ Inconsistent en passant square rejected or dropped${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Inconsistent en passant square kept: $RESPONSE${NC}"
+    ((FAIL++))
+fi
+
 test_case "9.5: Undo Count Validation"
 # Create game for undo test
 RESPONSE=$(api_request POST "$API_URL/games" \
@@ -811,12 +1370,12 @@ if [ -n "$UNDO_TEST_ID" ] && [ "$UNDO_TEST_ID" != "null" ]; then
         -H "Content-Type: application/json" \
         -d '{"move": "e2e4"}' > /dev/null
 
-    # Excessive undo count (over 300)
+    # Excessive undo count (over 100)
     RESPONSE=$(api_request POST "$API_URL/games/$UNDO_TEST_ID/undo" \
         -H "Content-Type: application/json" \
-        -d '{"count": 301}')
+        -d '{"count": 101}')
     ERROR_MSG=$(echo "$RESPONSE" | jq -r '.details' 2>/dev/null)
-    if [[ "$ERROR_MSG" == *"Count must be at most 300"* ]]; then
+    if [[ "$ERROR_MSG" == *"count must be at most 100"* ]]; then
         echo -e "${GREEN}  ✓ Excessive undo count rejected${NC}"
         ((PASS++))
     else
@@ -825,6 +1384,14 @@ if [ -n "$UNDO_TEST_ID" ] && [ "$UNDO_TEST_ID" != "null" ]; then
         ((FAIL++))
     fi
 
+    # A huge count is rejected, not clamped to the moves available
+    RESPONSE=$(api_request POST "$API_URL/games/$UNDO_TEST_ID/undo" \
+        -H "Content-Type: application/json" \
+        -d '{"count": 99999}')
+    assert_json_field "$RESPONSE" '.code' "INVALID_REQUEST" "Undo count 99999 rejected"
+    RESPONSE=$(api_request GET "$API_URL/games/$UNDO_TEST_ID")
+    assert_json_field "$RESPONSE" '.moves | length' "1" "Rejected undo left the move in place"
+
     api_request DELETE "$API_URL/games/$UNDO_TEST_ID" > /dev/null
 else
     echo -e "${YELLOW}  ⊘ Skipping undo validation tests${NC}"
@@ -844,7 +1411,7 @@ RESPONSE=$(api_request POST "$API_URL/games" \
     -H "Content-Type: application/json" \
     -d '{"white": {}}')
 ERROR_MSG=$(echo "$RESPONSE" | jq -r '.details' 2>/dev/null)
-if [[ "$ERROR_MSG" == *"Type is required"* ]]; then
+if [[ "$ERROR_MSG" == *"white.type is required"* ]]; then
     echo -e "${GREEN}  ✓ Missing required fields caught${NC}"
     ((PASS++))
 else
@@ -260,6 +260,41 @@ RESPONSE=$(api_request GET "$API_URL/auth/me" \
 assert_json_field "$RESPONSE" '.username' "$TEST_USER1" "Username matches"
 assert_json_field "$RESPONSE" '.email' "$TEST_EMAIL1" "Email matches"
 
+test_case "2.7: List and Revoke Sessions"
+RESPONSE=$(api_request POST "$API_URL/auth/login" \
+    -H "Content-Type: application/json" \
+    -H "User-Agent: test-db/1.0" \
+    -H "X-Forwarded-For: 203.0.113.7, 10.0.0.1" \
+    -d "{\"identifier\": \"$TEST_USER1\", \"password\": \"$TEST_PASS1\"}")
+TOKEN_ALICE=$(echo "$RESPONSE" | jq -r '.token' 2>/dev/null)
+RESPONSE=$(api_request GET "$API_URL/auth/sessions" \
+    -H "Authorization: Bearer $TOKEN_ALICE")
+assert_json_field "$RESPONSE" '.sessions[0].current' "true" "Current session listed"
+assert_json_field "$RESPONSE" '.sessions[0].userAgent' "test-db/1.0" "User agent recorded"
+# The test server trusts no proxy, so the forged X-Forwarded-For is ignored
+assert_json_field "$RESPONSE" '.sessions[0].ipAddress' "127.0.0.1" "Connection address recorded, not the forwarded one"
+# The earlier logins keep their own sessions until revoked
+SESSION_COUNT=$(echo "$RESPONSE" | jq -r '.sessions | length' 2>/dev/null)
+OTHER_SESSION=$(echo "$RESPONSE" | jq -r '.sessions[1].sessionId' 2>/dev/null)
+if [ "$SESSION_COUNT" -ge 2 ] 2>/dev/null; then
+    echo -e "${GREEN}  ✓ Earlier login sessions still listed${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Expected at least 2 sessions, got $SESSION_COUNT${NC}"
+    ((FAIL++))
+fi
+STATUS=$(api_request DELETE "$API_URL/auth/sessions/$OTHER_SESSION" \
+    -o /dev/null -w "%{http_code}" \
+    -H "Authorization: Bearer $TOKEN_ALICE")
+assert_status 204 "$STATUS" "Earlier session revoked"
+RESPONSE=$(api_request GET "$API_URL/auth/sessions" \
+    -H "Authorization: Bearer $TOKEN_ALICE")
+assert_json_field "$RESPONSE" '.sessions | length' "$((SESSION_COUNT - 1))" "Revoked session no longer listed"
+STATUS=$(api_request DELETE "$API_URL/auth/sessions/00000000-0000-0000-0000-000000000000" \
+    -o /dev/null -w "%{http_code}" \
+    -H "Authorization: Bearer $TOKEN_ALICE")
+assert_status 404 "$STATUS" "Unknown session not revoked"
+
 # ==============================================================================
 print_header "SECTION 3: Authenticated Game Creation"
 # ==============================================================================
@@ -342,6 +377,78 @@ else
     ((FAIL++))
 fi
 
+test_case "4.3: Game End Reasons Round-Trip Through Storage"
+# Games are ended through the API, the stored result and reason are then read back by query, show and export
+for ENDING in "0-1:checkmate:normal:f2f3 e7e5 g2g4 d8h4" \
+    "1/2-1/2:threefold_repetition:normal:g1f3 g8f6 f3g1 f6g8 g1f3 g8f6 f3g1 f6g8" \
+    "*:aborted:abandoned:abort"; do
+    IFS=: read -r RESULT REASON TERMINATION MOVES <<< "$ENDING"
+
+    END_GAME_ID=$(api_request POST "$API_URL/games" \
+        -H "Content-Type: application/json" \
+        -d '{"white": {"type": 1}, "black": {"type": 1}}' | jq -r '.gameId')
+    if [ "$MOVES" = "abort" ]; then
+        api_request POST "$API_URL/games/$END_GAME_ID/abort" > /dev/null
+    else
+        for MOVE in $MOVES; do
+            api_request POST "$API_URL/games/$END_GAME_ID/moves" \
+                -H "Content-Type: application/json" \
+                -d "{\"move\": \"$MOVE\"}" > /dev/null
+        done
+    fi
+    sleep 0.5  # Allow async write
+
+    STORED=$(sqlite3 "$TEST_DB" "SELECT result || ':' || reason FROM games WHERE game_id = '$END_GAME_ID' AND end_time_utc IS NOT NULL;" 2>/dev/null)
+    if [ "$STORED" = "$RESULT:$REASON" ]; then
+        echo -e "${GREEN}  ✓ Stored row has $RESULT ($REASON)${NC}"
+        ((PASS++))
+    else
+        echo -e "${RED}  ✗ Stored row has '$STORED', expected $RESULT:$REASON${NC}"
+        ((FAIL++))
+    fi
+
+    QUERY=$($CHESS_SERVER_EXEC db query -path "$TEST_DB" -gameId "$END_GAME_ID" -json 2>/dev/null)
+    assert_json_field "$QUERY" '.[0].reason' "$REASON" "Query reports $REASON"
+
+    if $CHESS_SERVER_EXEC db show -path "$TEST_DB" -gameId "$END_GAME_ID" 2>/dev/null | grep -qF "Result: $RESULT ($REASON)"; then
+        echo -e "${GREEN}  ✓ Show prints $RESULT ($REASON)${NC}"
+        ((PASS++))
+    else
+        echo -e "${RED}  ✗ Show does not print $RESULT ($REASON)${NC}"
+        ((FAIL++))
+    fi
+
+    PGN_FILE=$(mktemp)
+    $CHESS_SERVER_EXEC db export-pgn -path "$TEST_DB" -out "$PGN_FILE" > /dev/null 2>&1
+    if grep -A2 -F "[GameId \"$END_GAME_ID\"]" "$PGN_FILE" | grep -qF "[Termination \"$TERMINATION\"]"; then
+        echo -e "${GREEN}  ✓ PGN export tags $REASON as Termination \"$TERMINATION\"${NC}"
+        ((PASS++))
+    else
+        echo -e "${RED}  ✗ PGN export lacks Termination \"$TERMINATION\" for $REASON${NC}"
+        ((FAIL++))
+    fi
+    rm -f "$PGN_FILE"
+done
+
+test_case "4.4: Moves Stored With SAN"
+SAN_GAME_ID=$(api_request POST "$API_URL/games" \
+    -H "Content-Type: application/json" \
+    -d '{"white": {"type": 1}, "black": {"type": 1}}' | jq -r '.gameId')
+for MOVE in e2e4 e7e5 g1f3; do
+    api_request POST "$API_URL/games/$SAN_GAME_ID/moves" \
+        -H "Content-Type: application/json" \
+        -d "{\"move\": \"$MOVE\"}" > /dev/null
+done
+sleep 0.5  # Allow async write
+STORED_SAN=$(sqlite3 "$TEST_DB" "SELECT group_concat(move_san, ' ') FROM (SELECT move_san FROM moves WHERE game_id = '$SAN_GAME_ID' ORDER BY move_number);" 2>/dev/null)
+if [ "$STORED_SAN" = "e4 e5 Nf3" ]; then
+    echo -e "${GREEN}  ✓ SAN persisted with each move${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Expected stored SAN 'e4 e5 Nf3', got '$STORED_SAN'${NC}"
+    ((FAIL++))
+fi
+
 # ==============================================================================
 print_header "SECTION 5: Password Operations"
 # ==============================================================================
@@ -378,6 +485,23 @@ test_case "5.5: CLI rejects unsupported hash format"
 assert_command "$CHESS_SERVER_EXEC db user set-hash -path $TEST_DB -username $TEST_USER_CLI -hash '$UNSUPPORTED_HASH'" 1 \
     "Unsupported bcrypt hash rejected by CLI"
 
+test_case "5.6: Login Rehashes Password Made With Old Parameters"
+assert_command "$CHESS_SERVER_EXEC db user add -path $TEST_DB -username oldhash -password OldHash123 -argon2-memory 8192 -argon2-iterations 1 -argon2-parallelism 1" 0 \
+    "Add user with weak argon2 parameters"
+STATUS=$(api_request POST "$API_URL/auth/login" \
+    -o /dev/null -w "%{http_code}" \
+    -H "Content-Type: application/json" \
+    -d '{"identifier": "oldhash", "password": "OldHash123"}')
+assert_status 200 "$STATUS" "Login with old-parameter hash"
+STORED_HASH=$(sqlite3 "$TEST_DB" "SELECT password_hash FROM users WHERE username = 'oldhash';" 2>/dev/null)
+if [[ "$STORED_HASH" == *'$m=65536,t=3,p=4$'* ]]; then
+    echo -e "${GREEN}  ✓ Hash upgraded to server parameters${NC}"
+    ((PASS++))
+else
+    echo -e "${RED}  ✗ Hash not upgraded: $STORED_HASH${NC}"
+    ((FAIL++))
+fi
+
 # ==============================================================================
 print_header "SECTION 6: Edge Cases"
 # ==============================================================================